@@ -13,4 +13,28 @@ const (
 	//为了验证多集群
 	Kubeconfigs    = `{"TST-1":"E:\\GitHUB_Code_Check\\VUE\\kubea-go\\config\\k8s.yaml","TST-2":"E:\\GitHUB_Code_Check\\VUE\\kubea-go\\config\\k8s.yaml"}`
 	PodLogTailLine = 500
+	//日志搜索最多返回的匹配行数，以及允许设置的最大上下文行数
+	PodLogSearchMaxMatches = 1000
+	PodLogSearchMaxContext = 100
+	//通过exec下载和上传容器文件的大小上限(字节)
+	PodFileDownloadMaxSize = 1 << 30
+	PodFileUploadMaxSize   = 100 << 20
+	//允许建立websocket连接(终端、日志)的前端Origin，多个用逗号分隔，与接口同源的请求总是允许
+	WebsocketAllowedOrigins = "http://localhost:8080"
+	//等待deployment发布完成的默认超时时间(秒)，同时也是允许设置的最大值
+	RolloutStatusTimeout = 600
+	//drain node的默认超时时间(秒)，同时也是允许设置的最大值；违反PodDisruptionBudget时重试驱逐的最大间隔(秒)
+	DrainNodeTimeout           = 300
+	DrainEvictRetryMaxInterval = 10
+	//创建namespace时可选的ResourceQuota和LimitRange模板，key为模板名称
+	NamespaceTemplates = `{
+		"small": {
+			"resource_quota": {"requests.cpu": "4", "requests.memory": "8Gi", "limits.cpu": "8", "limits.memory": "16Gi", "pods": "50"},
+			"limit_range": {"default": {"cpu": "500m", "memory": "512Mi"}, "default_request": {"cpu": "100m", "memory": "128Mi"}}
+		},
+		"large": {
+			"resource_quota": {"requests.cpu": "16", "requests.memory": "32Gi", "limits.cpu": "32", "limits.memory": "64Gi", "pods": "200"},
+			"limit_range": {"default": {"cpu": "1", "memory": "1Gi"}, "default_request": {"cpu": "200m", "memory": "256Mi"}}
+		}
+	}`
 )
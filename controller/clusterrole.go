@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"kubea-go/service"
+	"net/http"
+
+	"github.com/aryming/logger"
+	"github.com/gin-gonic/gin"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  clusterrole.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-04-25 15:20
+ */
+
+var ClusterRole clusterRole
+
+type clusterRole struct{}
+
+// GetClusterRoles 获取clusterrole列表，支持过滤、排序、分页
+func (cr *clusterRole) GetClusterRoles(c *gin.Context) {
+	params := new(struct {
+		FilterName string `form:"filter_name"`
+		Page       int    `form:"page"`
+		Limit      int    `form:"limit"`
+		Cluster    string `form:"cluster"`
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.ClusterRole.GetClusterRoles(client, params.FilterName, params.Limit, params.Page)
+	if err != nil {
+		logger.Error("获取clusterrole列表失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "获取clusterrole列表成功",
+		"data": data,
+	})
+}
+
+// GetClusterRoleDetail 获取clusterrole详情
+func (cr *clusterRole) GetClusterRoleDetail(c *gin.Context) {
+	params := new(struct {
+		ClusterRoleName string `form:"clusterrole_name"`
+		Cluster         string `form:"cluster"`
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.ClusterRole.GetClusterRoleDetail(client, params.ClusterRoleName)
+	if err != nil {
+		logger.Error("获取clusterrole详情失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "获取clusterrole详情成功",
+		"data": data,
+	})
+}
@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"kubea-go/service"
+	"net/http"
+
+	"github.com/aryming/logger"
+	"github.com/gin-gonic/gin"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  clusterrolebinding.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-04-25 15:40
+ */
+
+var ClusterRoleBinding clusterRoleBinding
+
+type clusterRoleBinding struct{}
+
+// GetClusterRoleBindings 获取clusterrolebinding列表，支持过滤、排序、分页
+func (crb *clusterRoleBinding) GetClusterRoleBindings(c *gin.Context) {
+	params := new(struct {
+		FilterName string `form:"filter_name"`
+		Page       int    `form:"page"`
+		Limit      int    `form:"limit"`
+		Cluster    string `form:"cluster"`
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.ClusterRoleBinding.GetClusterRoleBindings(client, params.FilterName, params.Limit, params.Page)
+	if err != nil {
+		logger.Error("获取clusterrolebinding列表失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "获取clusterrolebinding列表成功",
+		"data": data,
+	})
+}
+
+// GetClusterRoleBindingDetail 获取clusterrolebinding详情
+func (crb *clusterRoleBinding) GetClusterRoleBindingDetail(c *gin.Context) {
+	params := new(struct {
+		ClusterRoleBindingName string `form:"clusterrolebinding_name"`
+		Cluster                string `form:"cluster"`
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.ClusterRoleBinding.GetClusterRoleBindingDetail(client, params.ClusterRoleBindingName)
+	if err != nil {
+		logger.Error("获取clusterrolebinding详情失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "获取clusterrolebinding详情成功",
+		"data": data,
+	})
+}
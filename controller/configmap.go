@@ -0,0 +1,214 @@
+package controller
+
+import (
+	"kubea-go/service"
+	"net/http"
+
+	"github.com/aryming/logger"
+	"github.com/gin-gonic/gin"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  configmap.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-04-11 11:12
+ */
+
+var ConfigMap configMap
+
+type configMap struct{}
+
+// GetConfigMaps 获取configmap列表，支持过滤、排序、分页
+func (cm *configMap) GetConfigMaps(c *gin.Context) {
+	params := new(struct {
+		FilterName string `form:"filter_name"`
+		Namespace  string `form:"namespace"`
+		Page       int    `form:"page"`
+		Limit      int    `form:"limit"`
+		Cluster    string `form:"cluster"`
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.ConfigMap.GetConfigMaps(client, params.FilterName, params.Namespace, params.Limit, params.Page)
+	if err != nil {
+		logger.Error("获取configmap列表失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "获取configmap列表成功",
+		"data": data,
+	})
+}
+
+// GetConfigMapDetail 获取configmap详情
+func (cm *configMap) GetConfigMapDetail(c *gin.Context) {
+	params := new(struct {
+		ConfigMapName string `form:"configmap_name"`
+		Namespace     string `form:"namespace"`
+		Cluster       string `form:"cluster"`
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.ConfigMap.GetConfigMapDetail(client, params.Namespace, params.ConfigMapName)
+	if err != nil {
+		logger.Error("获取configmap详情失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "获取configmap详情成功",
+		"data": data,
+	})
+}
+
+// CreateConfigMap 创建configmap
+func (cm *configMap) CreateConfigMap(c *gin.Context) {
+	var (
+		configMapCreate = new(service.ConfigMapCreate)
+		err             error
+	)
+	if err = c.ShouldBindJSON(configMapCreate); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(configMapCreate.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	if err := service.ConfigMap.CreateConfigMap(client, configMapCreate); err != nil {
+		logger.Error("创建configmap失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "创建configmap成功",
+		"data": nil,
+	})
+}
+
+// DeleteConfigMap 删除configmap
+func (cm *configMap) DeleteConfigMap(c *gin.Context) {
+	params := new(struct {
+		ConfigMapName string `json:"configmap_name"`
+		Namespace     string `json:"namespace"`
+		Cluster       string `json:"cluster"`
+	})
+	if err := c.ShouldBindJSON(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	if err := service.ConfigMap.DeleteConfigMap(client, params.ConfigMapName, params.Namespace); err != nil {
+		logger.Error("删除configmap失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "删除configmap成功",
+		"data": nil,
+	})
+}
+
+// UpdateConfigMap 更新configmap
+func (cm *configMap) UpdateConfigMap(c *gin.Context) {
+	params := new(struct {
+		Namespace string `json:"namespace"`
+		Content   string `json:"content"`
+		Cluster   string `json:"cluster"`
+	})
+	if err := c.ShouldBindJSON(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	if err := service.ConfigMap.UpdateConfigMap(client, params.Namespace, params.Content); err != nil {
+		logger.Error("更新configmap失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "更新configmap成功",
+		"data": nil,
+	})
+}
@@ -0,0 +1,142 @@
+package controller
+
+import (
+	"kubea-go/service"
+	"net/http"
+
+	"github.com/aryming/logger"
+	"github.com/gin-gonic/gin"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  crd.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-04-29 11:20
+ */
+
+var Crd crd
+
+type crd struct{}
+
+// GetCrds 获取CRD列表，包括版本和作用域
+func (cr *crd) GetCrds(c *gin.Context) {
+	params := new(struct {
+		FilterName string `form:"filter_name"`
+		Page       int    `form:"page"`
+		Limit      int    `form:"limit"`
+		Cluster    string `form:"cluster"`
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetDynamicClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.Crd.GetCrds(client, params.FilterName, params.Limit, params.Page)
+	if err != nil {
+		logger.Error("获取CRD列表失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "获取CRD列表成功",
+		"data": data,
+	})
+}
+
+// GetCrdDetail 获取CRD详情，包括OpenAPI schema和printer columns
+func (cr *crd) GetCrdDetail(c *gin.Context) {
+	params := new(struct {
+		CrdName string `form:"crd_name"`
+		Cluster string `form:"cluster"`
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetDynamicClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.Crd.GetCrdDetail(client, params.CrdName)
+	if err != nil {
+		logger.Error("获取CRD详情失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "获取CRD详情成功",
+		"data": data,
+	})
+}
+
+// GetCustomResources 获取CRD对应的自定义资源列表，返回与kubectl get一致的列
+func (cr *crd) GetCustomResources(c *gin.Context) {
+	params := new(struct {
+		CrdName    string `form:"crd_name"`
+		Version    string `form:"version"`
+		FilterName string `form:"filter_name"`
+		Namespace  string `form:"namespace"`
+		Page       int    `form:"page"`
+		Limit      int    `form:"limit"`
+		Cluster    string `form:"cluster"`
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetDynamicClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.Crd.GetCustomResources(client, params.CrdName, params.Version, params.FilterName, params.Namespace, params.Limit, params.Page)
+	if err != nil {
+		logger.Error("获取自定义资源列表失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "获取自定义资源列表成功",
+		"data": data,
+	})
+}
@@ -0,0 +1,293 @@
+package controller
+
+import (
+	"kubea-go/service"
+	"net/http"
+
+	"github.com/aryming/logger"
+	"github.com/gin-gonic/gin"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  cronjob.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-04-18 16:45
+ */
+
+var CronJob cronJob
+
+type cronJob struct{}
+
+// GetCronJobs 获取cronjob列表，支持过滤、排序、分页
+func (cj *cronJob) GetCronJobs(c *gin.Context) {
+	params := new(struct {
+		FilterName string `form:"filter_name"`
+		Namespace  string `form:"namespace"`
+		Page       int    `form:"page"`
+		Limit      int    `form:"limit"`
+		Cluster    string `form:"cluster"`
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.CronJob.GetCronJobs(client, params.FilterName, params.Namespace, params.Limit, params.Page)
+	if err != nil {
+		logger.Error("获取cronjob列表失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "获取cronjob列表成功",
+		"data": data,
+	})
+}
+
+// GetCronJobDetail 获取cronjob详情
+func (cj *cronJob) GetCronJobDetail(c *gin.Context) {
+	params := new(struct {
+		CronJobName string `form:"cronjob_name"`
+		Namespace   string `form:"namespace"`
+		Cluster     string `form:"cluster"`
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.CronJob.GetCronJobDetail(client, params.Namespace, params.CronJobName)
+	if err != nil {
+		logger.Error("获取cronjob详情失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "获取cronjob详情成功",
+		"data": data,
+	})
+}
+
+// DeleteCronJob 删除cronjob
+func (cj *cronJob) DeleteCronJob(c *gin.Context) {
+	params := new(struct {
+		CronJobName string `json:"cronjob_name"`
+		Namespace   string `json:"namespace"`
+		Cluster     string `json:"cluster"`
+	})
+	if err := c.ShouldBindJSON(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	if err := service.CronJob.DeleteCronJob(client, params.CronJobName, params.Namespace); err != nil {
+		logger.Error("删除cronjob失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "删除cronjob成功",
+		"data": nil,
+	})
+}
+
+// SuspendCronJob 暂停cronjob
+func (cj *cronJob) SuspendCronJob(c *gin.Context) {
+	params := new(struct {
+		CronJobName string `json:"cronjob_name"`
+		Namespace   string `json:"namespace"`
+		Cluster     string `json:"cluster"`
+	})
+	if err := c.ShouldBindJSON(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	if err := service.CronJob.SuspendCronJob(client, params.CronJobName, params.Namespace, true); err != nil {
+		logger.Error("暂停cronjob失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "暂停cronjob成功",
+		"data": nil,
+	})
+}
+
+// ResumeCronJob 恢复cronjob
+func (cj *cronJob) ResumeCronJob(c *gin.Context) {
+	params := new(struct {
+		CronJobName string `json:"cronjob_name"`
+		Namespace   string `json:"namespace"`
+		Cluster     string `json:"cluster"`
+	})
+	if err := c.ShouldBindJSON(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	if err := service.CronJob.SuspendCronJob(client, params.CronJobName, params.Namespace, false); err != nil {
+		logger.Error("恢复cronjob失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "恢复cronjob成功",
+		"data": nil,
+	})
+}
+
+// TriggerCronJob 立即触发cronjob，返回新建job的名称
+func (cj *cronJob) TriggerCronJob(c *gin.Context) {
+	params := new(struct {
+		CronJobName string `json:"cronjob_name"`
+		Namespace   string `json:"namespace"`
+		Cluster     string `json:"cluster"`
+	})
+	if err := c.ShouldBindJSON(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.CronJob.TriggerCronJob(client, params.CronJobName, params.Namespace)
+	if err != nil {
+		logger.Error("触发cronjob失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "触发cronjob成功",
+		"data": data,
+	})
+}
+
+// GetCronJobHistory 获取cronjob的job运行记录
+func (cj *cronJob) GetCronJobHistory(c *gin.Context) {
+	params := new(struct {
+		CronJobName string `form:"cronjob_name"`
+		Namespace   string `form:"namespace"`
+		Cluster     string `form:"cluster"`
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.CronJob.GetCronJobHistory(client, params.CronJobName, params.Namespace)
+	if err != nil {
+		logger.Error("获取cronjob运行记录失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "获取cronjob运行记录成功",
+		"data": data,
+	})
+}
@@ -0,0 +1,255 @@
+package controller
+
+import (
+	"kubea-go/service"
+	"net/http"
+
+	"github.com/aryming/logger"
+	"github.com/gin-gonic/gin"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  daemonset.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-04-03 15:02
+ */
+
+var DaemonSet daemonSet
+
+type daemonSet struct{}
+
+// GetDaemonSets 获取daemonset列表，支持过滤、排序、分页
+func (d *daemonSet) GetDaemonSets(c *gin.Context) {
+	params := new(struct {
+		FilterName string `form:"filter_name"`
+		Namespace  string `form:"namespace"`
+		Page       int    `form:"page"`
+		Limit      int    `form:"limit"`
+		Cluster    string `form:"cluster"`
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.DaemonSet.GetDaemonSets(client, params.FilterName, params.Namespace, params.Limit, params.Page)
+	if err != nil {
+		logger.Error("获取daemonset列表失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "获取daemonset列表成功",
+		"data": data,
+	})
+}
+
+// GetDaemonSetDetail 获取daemonset详情
+func (d *daemonSet) GetDaemonSetDetail(c *gin.Context) {
+	params := new(struct {
+		DaemonSetName string `form:"daemonset_name"`
+		Namespace     string `form:"namespace"`
+		Cluster       string `form:"cluster"`
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.DaemonSet.GetDaemonSetDetail(client, params.Namespace, params.DaemonSetName)
+	if err != nil {
+		logger.Error("获取daemonset详情失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "获取daemonset详情成功",
+		"data": data,
+	})
+}
+
+// DeleteDaemonSet 删除daemonset
+func (d *daemonSet) DeleteDaemonSet(c *gin.Context) {
+	params := new(struct {
+		DaemonSetName string `json:"daemonset_name"`
+		Namespace     string `json:"namespace"`
+		Cluster       string `json:"cluster"`
+	})
+	if err := c.ShouldBindJSON(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	if err := service.DaemonSet.DeleteDaemonSet(client, params.DaemonSetName, params.Namespace); err != nil {
+		logger.Error("删除daemonset失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "删除daemonset成功",
+		"data": nil,
+	})
+}
+
+// RestartDaemonSet 重启daemonset
+func (d *daemonSet) RestartDaemonSet(c *gin.Context) {
+	params := new(struct {
+		DaemonSetName string `json:"daemonset_name"`
+		Namespace     string `json:"namespace"`
+		Cluster       string `json:"cluster"`
+	})
+	if err := c.ShouldBindJSON(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.DaemonSet.RestartDaemonSet(client, params.DaemonSetName, params.Namespace)
+	if err != nil {
+		logger.Error("重启daemonset失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "重启daemonset成功",
+		"data": data,
+	})
+}
+
+// UpdateDaemonSet 更新daemonset
+func (d *daemonSet) UpdateDaemonSet(c *gin.Context) {
+	params := new(struct {
+		Namespace string `json:"namespace"`
+		Content   string `json:"content"`
+		Cluster   string `json:"cluster"`
+	})
+	if err := c.ShouldBindJSON(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	if err := service.DaemonSet.UpdateDaemonSet(client, params.Namespace, params.Content); err != nil {
+		logger.Error("更新daemonset失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "更新daemonset成功",
+		"data": nil,
+	})
+}
+
+// GetDaemonSetNodeStatus 获取daemonset在每个node上的发布状态
+func (d *daemonSet) GetDaemonSetNodeStatus(c *gin.Context) {
+	params := new(struct {
+		DaemonSetName string `form:"daemonset_name"`
+		Namespace     string `form:"namespace"`
+		Cluster       string `form:"cluster"`
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.DaemonSet.GetDaemonSetNodeStatus(client, params.DaemonSetName, params.Namespace)
+	if err != nil {
+		logger.Error("获取daemonset节点状态失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "获取daemonset节点状态成功",
+		"data": data,
+	})
+}
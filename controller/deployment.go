@@ -176,8 +176,13 @@ func (d *deployment) ScaleDeployment(c *gin.Context) {
 		})
 		return
 	}
+	msg := "设置deployment副本数成功"
+	// deployment受hpa控制时，手动设置的副本数会被hpa覆盖，需要提示调用方
+	if hpa, err := service.Hpa.GetDeploymentHpa(client, params.Namespace, params.DeploymentName); err == nil && hpa != nil {
+		msg += ", " + service.Hpa.ScaleWarning(hpa)
+	}
 	c.JSON(http.StatusOK, gin.H{
-		"msg":  "设置deployment副本数成功",
+		"msg":  msg,
 		"data": fmt.Sprintf("最新副本数: %d", data),
 	})
 }
@@ -221,12 +226,12 @@ func (d *deployment) DeleteDeployment(c *gin.Context) {
 	})
 }
 
-// RestartDeployment 重启deployment
+// RestartDeployment 重启deployment，返回重启生成的新版本
 func (d *deployment) RestartDeployment(c *gin.Context) {
 	params := new(struct {
-		DeploymentName string `form:"deployment_name"`
-		Namespace      string `form:"namespace"`
-		Cluster        string `form:"cluster"`
+		DeploymentName string `json:"deployment_name"`
+		Namespace      string `json:"namespace"`
+		Cluster        string `json:"cluster"`
 	})
 	// PUT 请求，绑定参数方法改为c.ShouldBindJSON
 	if err := c.ShouldBindJSON(params); err != nil {
@@ -246,7 +251,8 @@ func (d *deployment) RestartDeployment(c *gin.Context) {
 		})
 		return
 	}
-	if err := service.Deployment.RestartDeployment(client, params.DeploymentName, params.Namespace); err != nil {
+	data, err := service.Deployment.RestartDeployment(client, params.DeploymentName, params.Namespace)
+	if err != nil {
 		logger.Error("重启deployment失败," + err.Error())
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"msg":  err.Error(),
@@ -256,7 +262,7 @@ func (d *deployment) RestartDeployment(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, gin.H{
 		"msg":  "重启deployment成功",
-		"data": nil,
+		"data": data,
 	})
 
 }
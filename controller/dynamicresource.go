@@ -0,0 +1,271 @@
+package controller
+
+import (
+	"kubea-go/service"
+	"net/http"
+
+	"github.com/aryming/logger"
+	"github.com/gin-gonic/gin"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  dynamicresource.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-04-28 11:30
+ */
+
+var DynamicResource dynamicResource
+
+type dynamicResource struct{}
+
+// GetApiResources 获取集群支持的资源类型
+func (d *dynamicResource) GetApiResources(c *gin.Context) {
+	params := new(struct {
+		Cluster string `form:"cluster"`
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.DynamicResource.GetApiResources(client)
+	if err != nil {
+		logger.Error("获取资源类型失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "获取资源类型成功",
+		"data": data,
+	})
+}
+
+// GetResources 获取任意资源类型的对象列表，支持过滤、排序、分页
+func (d *dynamicResource) GetResources(c *gin.Context) {
+	params := new(struct {
+		FilterName string `form:"filter_name"`
+		Namespace  string `form:"namespace"`
+		Page       int    `form:"page"`
+		Limit      int    `form:"limit"`
+		Cluster    string `form:"cluster"`
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, dynamicClient, err := d.getClients(params.Cluster)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.DynamicResource.GetResources(client, dynamicClient, c.Param("group"), c.Param("version"), c.Param("resource"),
+		params.FilterName, params.Namespace, params.Limit, params.Page)
+	if err != nil {
+		logger.Error("获取资源列表失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "获取资源列表成功",
+		"data": data,
+	})
+}
+
+// GetResourceDetail 获取任意资源类型的对象详情
+func (d *dynamicResource) GetResourceDetail(c *gin.Context) {
+	params := new(struct {
+		Namespace string `form:"namespace"`
+		Cluster   string `form:"cluster"`
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, dynamicClient, err := d.getClients(params.Cluster)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.DynamicResource.GetResourceDetail(client, dynamicClient, c.Param("group"), c.Param("version"), c.Param("resource"),
+		params.Namespace, c.Param("name"))
+	if err != nil {
+		logger.Error("获取资源详情失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "获取资源详情成功",
+		"data": data,
+	})
+}
+
+// CreateResource 创建任意资源类型的对象，content为对象的完整json
+func (d *dynamicResource) CreateResource(c *gin.Context) {
+	params := new(struct {
+		Namespace string `json:"namespace"`
+		Content   string `json:"content"`
+		Cluster   string `json:"cluster"`
+	})
+	if err := c.ShouldBindJSON(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, dynamicClient, err := d.getClients(params.Cluster)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.DynamicResource.CreateResource(client, dynamicClient, c.Param("group"), c.Param("version"), c.Param("resource"),
+		params.Namespace, params.Content)
+	if err != nil {
+		logger.Error("创建资源失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "创建资源成功",
+		"data": data,
+	})
+}
+
+// PatchResource patch任意资源类型的对象，patch_type为merge(默认)、json或strategic
+func (d *dynamicResource) PatchResource(c *gin.Context) {
+	params := new(struct {
+		Namespace string `json:"namespace"`
+		PatchType string `json:"patch_type"`
+		Content   string `json:"content"`
+		Cluster   string `json:"cluster"`
+	})
+	if err := c.ShouldBindJSON(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, dynamicClient, err := d.getClients(params.Cluster)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.DynamicResource.PatchResource(client, dynamicClient, c.Param("group"), c.Param("version"), c.Param("resource"),
+		params.Namespace, c.Param("name"), params.PatchType, params.Content)
+	if err != nil {
+		logger.Error("更新资源失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "更新资源成功",
+		"data": data,
+	})
+}
+
+// DeleteResource 删除任意资源类型的对象
+func (d *dynamicResource) DeleteResource(c *gin.Context) {
+	params := new(struct {
+		Namespace string `json:"namespace"`
+		Cluster   string `json:"cluster"`
+	})
+	if err := c.ShouldBindJSON(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, dynamicClient, err := d.getClients(params.Cluster)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	err = service.DynamicResource.DeleteResource(client, dynamicClient, c.Param("group"), c.Param("version"), c.Param("resource"),
+		params.Namespace, c.Param("name"))
+	if err != nil {
+		logger.Error("删除资源失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "删除资源成功",
+		"data": nil,
+	})
+}
+
+// getClients 获取集群的Clientset(用于discovery)和DynamicClient
+func (d *dynamicResource) getClients(cluster string) (*kubernetes.Clientset, *dynamic.DynamicClient, error) {
+	client, err := service.K8s.GetClient(cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		return nil, nil, err
+	}
+	dynamicClient, err := service.K8s.GetDynamicClient(cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		return nil, nil, err
+	}
+	return client, dynamicClient, nil
+}
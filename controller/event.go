@@ -0,0 +1,72 @@
+package controller
+
+import (
+	"kubea-go/service"
+	"net/http"
+
+	"github.com/aryming/logger"
+	"github.com/gin-gonic/gin"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  event.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-04-21 11:02
+ */
+
+var Event event
+
+type event struct{}
+
+// GetEvents 获取event列表，支持按类型、原因、关联对象过滤以及分页，namespace为空时获取整个集群
+func (e *event) GetEvents(c *gin.Context) {
+	params := new(struct {
+		FilterName string `form:"filter_name"`
+		Namespace  string `form:"namespace"`
+		Type       string `form:"type"`
+		Reason     string `form:"reason"`
+		Kind       string `form:"kind"`
+		Name       string `form:"name"`
+		Page       int    `form:"page"`
+		Limit      int    `form:"limit"`
+		Cluster    string `form:"cluster"`
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	filter := &service.EventFilter{
+		Type:   params.Type,
+		Reason: params.Reason,
+		Kind:   params.Kind,
+		Name:   params.Name,
+	}
+	data, err := service.Event.GetEvents(client, params.FilterName, params.Namespace, filter, params.Limit, params.Page)
+	if err != nil {
+		logger.Error("获取event列表失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "获取event列表成功",
+		"data": data,
+	})
+}
@@ -0,0 +1,158 @@
+package controller
+
+import (
+	"kubea-go/service"
+	"net/http"
+
+	"github.com/aryming/logger"
+	"github.com/gin-gonic/gin"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  namespace.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-04-16 11:30
+ */
+
+var Namespace namespace
+
+type namespace struct{}
+
+// GetNamespaces 获取namespace列表，支持过滤、排序、分页
+func (n *namespace) GetNamespaces(c *gin.Context) {
+	params := new(struct {
+		FilterName string `form:"filter_name"`
+		Page       int    `form:"page"`
+		Limit      int    `form:"limit"`
+		Cluster    string `form:"cluster"`
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.Namespace.GetNamespaces(client, params.FilterName, params.Limit, params.Page)
+	if err != nil {
+		logger.Error("获取namespace列表失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "获取namespace列表成功",
+		"data": data,
+	})
+}
+
+// CreateNamespace 创建namespace，可选应用配额模板
+func (n *namespace) CreateNamespace(c *gin.Context) {
+	var (
+		namespaceCreate = new(service.NamespaceCreate)
+		err             error
+	)
+	if err = c.ShouldBindJSON(namespaceCreate); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(namespaceCreate.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	if err := service.Namespace.CreateNamespace(client, namespaceCreate); err != nil {
+		logger.Error("创建namespace失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "创建namespace成功",
+		"data": nil,
+	})
+}
+
+// GetNamespaceTemplates 获取可用的namespace配额模板
+func (n *namespace) GetNamespaceTemplates(c *gin.Context) {
+	data, err := service.Namespace.GetNamespaceTemplates()
+	if err != nil {
+		logger.Error("获取namespace模板失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "获取namespace模板成功",
+		"data": data,
+	})
+}
+
+// DeleteNamespace 删除namespace，confirm为false时只返回将被删除的资源清单
+func (n *namespace) DeleteNamespace(c *gin.Context) {
+	params := new(struct {
+		NamespaceName string `json:"namespace_name"`
+		Confirm       bool   `json:"confirm"`
+		Cluster       string `json:"cluster"`
+	})
+	if err := c.ShouldBindJSON(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.Namespace.DeleteNamespace(client, params.NamespaceName, params.Confirm)
+	if err != nil {
+		logger.Error("删除namespace失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	msg := "删除namespace成功"
+	if !data.Deleted {
+		msg = "以下资源将随namespace一起删除，确认后请携带confirm=true重新提交"
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  msg,
+		"data": data,
+	})
+}
@@ -0,0 +1,257 @@
+package controller
+
+import (
+	"kubea-go/service"
+	"net/http"
+
+	"github.com/aryming/logger"
+	"github.com/gin-gonic/gin"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  networkpolicy.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-04-27 11:30
+ */
+
+var NetworkPolicy networkPolicy
+
+type networkPolicy struct{}
+
+// GetNetworkPolicies 获取networkpolicy列表，支持过滤、排序、分页
+func (n *networkPolicy) GetNetworkPolicies(c *gin.Context) {
+	params := new(struct {
+		FilterName string `form:"filter_name"`
+		Namespace  string `form:"namespace"`
+		Page       int    `form:"page"`
+		Limit      int    `form:"limit"`
+		Cluster    string `form:"cluster"`
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.NetworkPolicy.GetNetworkPolicies(client, params.FilterName, params.Namespace, params.Limit, params.Page)
+	if err != nil {
+		logger.Error("获取networkpolicy列表失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "获取networkpolicy列表成功",
+		"data": data,
+	})
+}
+
+// GetNetworkPolicyDetail 获取networkpolicy详情
+func (n *networkPolicy) GetNetworkPolicyDetail(c *gin.Context) {
+	params := new(struct {
+		NetworkPolicyName string `form:"networkpolicy_name"`
+		Namespace         string `form:"namespace"`
+		Cluster           string `form:"cluster"`
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.NetworkPolicy.GetNetworkPolicyDetail(client, params.Namespace, params.NetworkPolicyName)
+	if err != nil {
+		logger.Error("获取networkpolicy详情失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "获取networkpolicy详情成功",
+		"data": data,
+	})
+}
+
+// CreateNetworkPolicy 创建networkpolicy
+func (n *networkPolicy) CreateNetworkPolicy(c *gin.Context) {
+	var (
+		networkPolicyCreate = new(service.NetworkPolicyCreate)
+		err                 error
+	)
+	if err = c.ShouldBindJSON(networkPolicyCreate); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(networkPolicyCreate.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	if err := service.NetworkPolicy.CreateNetworkPolicy(client, networkPolicyCreate); err != nil {
+		logger.Error("创建networkpolicy失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "创建networkpolicy成功",
+		"data": nil,
+	})
+}
+
+// UpdateNetworkPolicy 更新networkpolicy
+func (n *networkPolicy) UpdateNetworkPolicy(c *gin.Context) {
+	params := new(struct {
+		Namespace string `json:"namespace"`
+		Content   string `json:"content"`
+		Cluster   string `json:"cluster"`
+	})
+	if err := c.ShouldBindJSON(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	if err := service.NetworkPolicy.UpdateNetworkPolicy(client, params.Namespace, params.Content); err != nil {
+		logger.Error("更新networkpolicy失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "更新networkpolicy成功",
+		"data": nil,
+	})
+}
+
+// DeleteNetworkPolicy 删除networkpolicy
+func (n *networkPolicy) DeleteNetworkPolicy(c *gin.Context) {
+	params := new(struct {
+		NetworkPolicyName string `json:"networkpolicy_name"`
+		Namespace         string `json:"namespace"`
+		Cluster           string `json:"cluster"`
+	})
+	if err := c.ShouldBindJSON(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	if err := service.NetworkPolicy.DeleteNetworkPolicy(client, params.NetworkPolicyName, params.Namespace); err != nil {
+		logger.Error("删除networkpolicy失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "删除networkpolicy成功",
+		"data": nil,
+	})
+}
+
+// EvaluateReachability 判断源pod访问目标pod端口的流量是否被networkpolicy放行
+func (n *networkPolicy) EvaluateReachability(c *gin.Context) {
+	params := new(struct {
+		SourceNamespace      string `form:"source_namespace"`
+		SourcePod            string `form:"source_pod"`
+		DestinationNamespace string `form:"destination_namespace"`
+		DestinationPod       string `form:"destination_pod"`
+		Port                 int32  `form:"port"`
+		Protocol             string `form:"protocol"`
+		Cluster              string `form:"cluster"`
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.NetworkPolicy.EvaluateReachability(client, &service.ReachabilityQuery{SourceNamespace: params.SourceNamespace, SourcePod: params.SourcePod, DestinationNamespace: params.DestinationNamespace, DestinationPod: params.DestinationPod, Port: params.Port, Protocol: params.Protocol})
+	if err != nil {
+		logger.Error("判断pod连通性失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "判断pod连通性成功",
+		"data": data,
+	})
+}
@@ -0,0 +1,224 @@
+package controller
+
+import (
+	"context"
+	"kubea-go/config"
+	"kubea-go/service"
+	"net/http"
+	"time"
+
+	"github.com/aryming/logger"
+	"github.com/gin-gonic/gin"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  node.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-04-14 10:35
+ */
+
+var Node node
+
+type node struct{}
+
+// GetNodes 获取node列表，支持过滤、排序、分页
+func (n *node) GetNodes(c *gin.Context) {
+	params := new(struct {
+		FilterName string `form:"filter_name"`
+		Page       int    `form:"page"`
+		Limit      int    `form:"limit"`
+		Cluster    string `form:"cluster"`
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.Node.GetNodes(client, params.FilterName, params.Limit, params.Page)
+	if err != nil {
+		logger.Error("获取node列表失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "获取node列表成功",
+		"data": data,
+	})
+}
+
+// GetNodeDetail 获取node详情
+func (n *node) GetNodeDetail(c *gin.Context) {
+	params := new(struct {
+		NodeName string `form:"node_name"`
+		Cluster  string `form:"cluster"`
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.Node.GetNodeDetail(client, params.NodeName)
+	if err != nil {
+		logger.Error("获取node详情失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "获取node详情成功",
+		"data": data,
+	})
+}
+
+// CordonNode 设置node为不可调度
+func (n *node) CordonNode(c *gin.Context) {
+	params := new(struct {
+		NodeName string `json:"node_name"`
+		Cluster  string `json:"cluster"`
+	})
+	if err := c.ShouldBindJSON(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	if err := service.Node.CordonNode(client, params.NodeName); err != nil {
+		logger.Error("cordon node失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "cordon node成功",
+		"data": nil,
+	})
+}
+
+// UncordonNode 恢复node为可调度
+func (n *node) UncordonNode(c *gin.Context) {
+	params := new(struct {
+		NodeName string `json:"node_name"`
+		Cluster  string `json:"cluster"`
+	})
+	if err := c.ShouldBindJSON(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	if err := service.Node.UncordonNode(client, params.NodeName); err != nil {
+		logger.Error("uncordon node失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "uncordon node成功",
+		"data": nil,
+	})
+}
+
+// DrainNode 排空node，返回每个pod的驱逐结果
+// 违反PodDisruptionBudget的pod会重试驱逐，直到timeout秒后仍未成功时记为失败
+func (n *node) DrainNode(c *gin.Context) {
+	params := new(struct {
+		NodeName           string `json:"node_name"`
+		GracePeriodSeconds *int64 `json:"grace_period_seconds"`
+		Timeout            int    `json:"timeout"`
+		Cluster            string `json:"cluster"`
+	})
+	if err := c.ShouldBindJSON(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	timeout := params.Timeout
+	if timeout <= 0 || timeout > config.DrainNodeTimeout {
+		timeout = config.DrainNodeTimeout
+	}
+	// 客户端断开连接时request的context会被取消，停止重试
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(timeout)*time.Second)
+	defer cancel()
+	data, err := service.Node.DrainNode(ctx, client, params.NodeName, params.GracePeriodSeconds)
+	if err != nil {
+		logger.Error("drain node失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "drain node成功",
+		"data": data,
+	})
+}
@@ -0,0 +1,199 @@
+package controller
+
+import (
+	"kubea-go/config"
+	"kubea-go/service"
+	"mime"
+	"net/http"
+	"path"
+
+	"github.com/aryming/logger"
+	"github.com/gin-gonic/gin"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  podfile.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-05-11 10:00
+ */
+
+var PodFile podFile
+
+type podFile struct{}
+
+// ListFiles 列出容器中目录下的文件
+func (f *podFile) ListFiles(c *gin.Context) {
+	params := new(struct {
+		Namespace     string `form:"namespace"`
+		PodName       string `form:"pod_name"`
+		ContainerName string `form:"container_name"`
+		Path          string `form:"path"`
+		Cluster       string `form:"cluster"`
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, restConfig, err := f.getClients(params.Cluster)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.PodFile.ListFiles(c.Request.Context(), client, restConfig, params.Namespace, params.PodName, params.ContainerName, params.Path)
+	if err != nil {
+		logger.Error("获取容器文件列表失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "获取容器文件列表成功",
+		"data": data,
+	})
+}
+
+// DownloadFile 将容器中的文件或目录打包下载，format为tar(默认)或zip
+func (f *podFile) DownloadFile(c *gin.Context) {
+	params := new(struct {
+		Namespace     string `form:"namespace"`
+		PodName       string `form:"pod_name"`
+		ContainerName string `form:"container_name"`
+		Path          string `form:"path"`
+		Format        string `form:"format"`
+		Cluster       string `form:"cluster"`
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, restConfig, err := f.getClients(params.Cluster)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	// 开始传输后无法再返回json格式的错误，先检查文件是否存在以及大小
+	err = service.PodFile.CheckDownloadSize(c.Request.Context(), client, restConfig, params.Namespace, params.PodName, params.ContainerName, params.Path)
+	if err != nil {
+		logger.Error("下载容器文件失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	format := service.PodFileFormatTar
+	if params.Format == service.PodFileFormatZip {
+		format = service.PodFileFormatZip
+	}
+	c.Header("Content-Type", "application/"+format)
+	// 下载根目录时path.Base返回"/"，使用root作为文件名
+	fileName := path.Base(params.Path)
+	if fileName == "/" || fileName == "." {
+		fileName = "root"
+	}
+	// 文件名可能包含空格、引号或非ASCII字符，由mime.FormatMediaType加引号或编码
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName + "." + format}))
+	c.Status(http.StatusOK)
+	err = service.PodFile.DownloadFile(c.Request.Context(), client, restConfig, params.Namespace, params.PodName, params.ContainerName, params.Path, format, c.Writer)
+	if err != nil {
+		// 响应头已经发送，只能记录错误，客户端收到的是不完整的文件
+		logger.Error("下载容器文件失败," + err.Error())
+	}
+}
+
+// UploadFile 上传文件到容器的目录中，请求为multipart/form-data，文件字段为file
+func (f *podFile) UploadFile(c *gin.Context) {
+	// 限制请求体大小，预留multipart其他字段的空间
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, config.PodFileUploadMaxSize+1<<20)
+	params := new(struct {
+		Namespace     string `form:"namespace"`
+		PodName       string `form:"pod_name"`
+		ContainerName string `form:"container_name"`
+		Path          string `form:"path"`
+		Cluster       string `form:"cluster"`
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		logger.Error("获取上传文件失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, restConfig, err := f.getClients(params.Cluster)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		logger.Error("读取上传文件失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	defer file.Close()
+	err = service.PodFile.UploadFile(c.Request.Context(), client, restConfig, params.Namespace, params.PodName, params.ContainerName, params.Path,
+		fileHeader.Filename, fileHeader.Size, file)
+	if err != nil {
+		logger.Error("上传容器文件失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "上传容器文件成功",
+		"data": nil,
+	})
+}
+
+// getClients 获取集群的Clientset和exec需要的rest.Config
+func (f *podFile) getClients(cluster string) (*kubernetes.Clientset, *rest.Config, error) {
+	client, err := service.K8s.GetClient(cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		return nil, nil, err
+	}
+	restConfig, err := service.K8s.GetRestConfig(cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		return nil, nil, err
+	}
+	return client, restConfig, nil
+}
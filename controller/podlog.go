@@ -0,0 +1,269 @@
+package controller
+
+import (
+	"compress/gzip"
+	"context"
+	"kubea-go/service"
+	"net/http"
+	"time"
+
+	"github.com/aryming/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  podlog.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-05-09 10:00
+ */
+
+// 向websocket写入日志的超时时间
+const logWriteTimeout = 10 * time.Second
+
+// FollowPodLog 持续推送容器日志，websocket请求时每行日志为一条文本消息，否则以SSE推送log事件
+// 容器退出时SSE推送end事件、websocket正常关闭，客户端断开连接时停止读取日志
+func (p *pod) FollowPodLog(c *gin.Context) {
+	params := new(struct {
+		Namespace string `form:"namespace"`
+		PodName   string `form:"pod_name"`
+		Cluster   string `form:"cluster"`
+		service.PodLogQuery
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	follow := func(ctx context.Context, onLine func(line string) error) error {
+		return service.Pod.FollowPodLog(ctx, client, params.Namespace, params.PodName, &params.PodLogQuery, onLine)
+	}
+	if websocket.IsWebSocketUpgrade(c.Request) {
+		followLogWebsocket(c, follow)
+		return
+	}
+	followLogSSE(c, follow)
+}
+
+// followLogSSE 以SSE推送日志，客户端断开连接时request的context会被取消
+func followLogSSE(c *gin.Context, follow func(ctx context.Context, onLine func(line string) error) error) {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	err := follow(c.Request.Context(), func(line string) error {
+		c.SSEvent("log", line)
+		c.Writer.Flush()
+		return nil
+	})
+	if err != nil {
+		c.SSEvent("error", err.Error())
+	} else {
+		c.SSEvent("end", "")
+	}
+	c.Writer.Flush()
+}
+
+// followLogWebsocket 以websocket推送日志，websocket连接被hijack后request的context不会被取消
+// 需要持续读取客户端消息来感知连接断开
+func followLogWebsocket(c *gin.Context, follow func(ctx context.Context, onLine func(line string) error) error) {
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		logger.Error("升级websocket连接失败," + err.Error())
+		return
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+	err = follow(ctx, func(line string) error {
+		_ = conn.SetWriteDeadline(time.Now().Add(logWriteTimeout))
+		return conn.WriteMessage(websocket.TextMessage, []byte(line))
+	})
+	closeCode, reason := websocket.CloseNormalClosure, "日志已结束"
+	if err != nil {
+		closeCode, reason = websocket.CloseInternalServerErr, "获取日志失败"
+		_ = conn.SetWriteDeadline(time.Now().Add(logWriteTimeout))
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(err.Error()))
+	}
+	_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(closeCode, reason), time.Now().Add(logWriteTimeout))
+}
+
+// FollowWorkloadLog 持续推送工作负载或label selector匹配的所有pod的合并日志，每行以[pod/container]为前缀
+// kind默认为deployment，设置selector时忽略kind和name，推送方式与FollowPodLog相同
+func (p *pod) FollowWorkloadLog(c *gin.Context) {
+	params := new(struct {
+		Namespace string `form:"namespace"`
+		Kind      string `form:"kind"`
+		Name      string `form:"name"`
+		Selector  string `form:"selector"`
+		Cluster   string `form:"cluster"`
+		service.PodLogQuery
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	selector := params.Selector
+	if selector == "" {
+		kind := params.Kind
+		if kind == "" {
+			kind = service.WorkloadDeployment
+		}
+		if selector, err = service.Workload.GetSelector(client, kind, params.Namespace, params.Name); err != nil {
+			logger.Error("获取工作负载selector失败," + err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"msg":  err.Error(),
+				"data": nil,
+			})
+			return
+		}
+	}
+	follow := func(ctx context.Context, onLine func(line string) error) error {
+		return service.Pod.FollowSelectorLog(ctx, client, params.Namespace, selector, &params.PodLogQuery, func(line *service.PodLogLine) error {
+			return onLine(line.String())
+		})
+	}
+	if websocket.IsWebSocketUpgrade(c.Request) {
+		followLogWebsocket(c, follow)
+		return
+	}
+	followLogSSE(c, follow)
+}
+
+// DownloadPodLog 以gzip文件下载容器日志，tail_lines为空时下载全部日志，可通过since_time和until_time指定时间范围
+func (p *pod) DownloadPodLog(c *gin.Context) {
+	params := new(struct {
+		Namespace string `form:"namespace"`
+		PodName   string `form:"pod_name"`
+		Cluster   string `form:"cluster"`
+		service.PodLogQuery
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	fileName := params.PodName
+	if params.Container != "" {
+		fileName += "-" + params.Container
+	}
+	fileName += "-" + time.Now().Format("20060102150405") + ".log.gz"
+	// 读取到第一行日志时才写入响应头，获取日志失败时仍可以返回json格式的错误
+	var gzipWriter *gzip.Writer
+	start := func() {
+		c.Header("Content-Type", "application/gzip")
+		c.Header("Content-Disposition", "attachment; filename="+fileName)
+		c.Status(http.StatusOK)
+		gzipWriter = gzip.NewWriter(c.Writer)
+	}
+	err = service.Pod.ReadPodLog(c.Request.Context(), client, params.Namespace, params.PodName, &params.PodLogQuery, func(line string) error {
+		if gzipWriter == nil {
+			start()
+		}
+		_, err := gzipWriter.Write([]byte(line + "\n"))
+		return err
+	})
+	if err != nil && gzipWriter == nil {
+		logger.Error("下载pod日志失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	if err != nil {
+		// 响应头已经发送，只能记录错误，客户端收到的是不完整的文件
+		logger.Error("下载pod日志失败," + err.Error())
+	}
+	if gzipWriter == nil {
+		start()
+	}
+	_ = gzipWriter.Close()
+}
+
+// SearchPodLog 在服务端搜索容器日志，返回匹配行及前后context行，tail_lines为空时搜索全部日志
+func (p *pod) SearchPodLog(c *gin.Context) {
+	params := new(struct {
+		Namespace string `form:"namespace"`
+		PodName   string `form:"pod_name"`
+		Cluster   string `form:"cluster"`
+		service.PodLogQuery
+		service.PodLogSearch
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.Pod.SearchPodLog(c.Request.Context(), client, params.Namespace, params.PodName, &params.PodLogQuery, &params.PodLogSearch)
+	if err != nil {
+		logger.Error("搜索pod日志失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "搜索pod日志成功",
+		"data": data,
+	})
+}
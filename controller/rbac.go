@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"kubea-go/service"
+	"net/http"
+
+	"github.com/aryming/logger"
+	"github.com/gin-gonic/gin"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  rbac.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-04-25 16:00
+ */
+
+var Rbac rbac
+
+type rbac struct{}
+
+// WhoCan 计算主体在namespace中的有效权限
+func (r *rbac) WhoCan(c *gin.Context) {
+	params := new(struct {
+		SubjectKind      string `form:"subject_kind"`
+		SubjectName      string `form:"subject_name"`
+		SubjectNamespace string `form:"subject_namespace"`
+		Namespace        string `form:"namespace"`
+		Cluster          string `form:"cluster"`
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.Rbac.WhoCan(client, &service.RbacSubject{Kind: params.SubjectKind, Name: params.SubjectName, Namespace: params.SubjectNamespace}, params.Namespace)
+	if err != nil {
+		logger.Error("获取主体权限失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "获取主体权限成功",
+		"data": data,
+	})
+}
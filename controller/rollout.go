@@ -0,0 +1,313 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"kubea-go/config"
+	"kubea-go/service"
+	"net/http"
+	"time"
+
+	"github.com/aryming/logger"
+	"github.com/gin-gonic/gin"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  rollout.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-04-30 14:30
+ */
+
+// GetDeploymentHistory 获取deployment的历史版本
+func (d *deployment) GetDeploymentHistory(c *gin.Context) {
+	params := new(struct {
+		DeploymentName string `form:"deployment_name"`
+		Namespace      string `form:"namespace"`
+		Cluster        string `form:"cluster"`
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.Deployment.GetDeploymentHistory(client, params.Namespace, params.DeploymentName)
+	if err != nil {
+		logger.Error("获取deployment历史版本失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "获取deployment历史版本成功",
+		"data": data,
+	})
+}
+
+// DiffDeploymentRevisions 对比deployment两个版本的pod模板
+func (d *deployment) DiffDeploymentRevisions(c *gin.Context) {
+	params := new(struct {
+		DeploymentName string `form:"deployment_name"`
+		Namespace      string `form:"namespace"`
+		From           int64  `form:"from"`
+		To             int64  `form:"to"`
+		Cluster        string `form:"cluster"`
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.Deployment.DiffDeploymentRevisions(client, params.Namespace, params.DeploymentName, params.From, params.To)
+	if err != nil {
+		logger.Error("对比deployment版本失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "对比deployment版本成功",
+		"data": data,
+	})
+}
+
+// RollbackDeployment 回滚deployment到指定版本
+func (d *deployment) RollbackDeployment(c *gin.Context) {
+	params := new(struct {
+		DeploymentName string `json:"deployment_name"`
+		Namespace      string `json:"namespace"`
+		Revision       int64  `json:"revision"`
+		Cluster        string `json:"cluster"`
+	})
+	if err := c.ShouldBindJSON(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	if err := service.Deployment.RollbackDeployment(client, params.Namespace, params.DeploymentName, params.Revision); err != nil {
+		logger.Error("回滚deployment失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "回滚deployment成功",
+		"data": nil,
+	})
+}
+
+// PauseDeployment 暂停deployment的发布
+func (d *deployment) PauseDeployment(c *gin.Context) {
+	params := new(struct {
+		DeploymentName string `json:"deployment_name"`
+		Namespace      string `json:"namespace"`
+		Cluster        string `json:"cluster"`
+	})
+	if err := c.ShouldBindJSON(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	if err := service.Deployment.PauseDeployment(client, params.Namespace, params.DeploymentName, true); err != nil {
+		logger.Error("暂停deployment失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "暂停deployment成功",
+		"data": nil,
+	})
+}
+
+// ResumeDeployment 恢复deployment的发布
+func (d *deployment) ResumeDeployment(c *gin.Context) {
+	params := new(struct {
+		DeploymentName string `json:"deployment_name"`
+		Namespace      string `json:"namespace"`
+		Cluster        string `json:"cluster"`
+	})
+	if err := c.ShouldBindJSON(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	if err := service.Deployment.PauseDeployment(client, params.Namespace, params.DeploymentName, false); err != nil {
+		logger.Error("恢复deployment失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "恢复deployment成功",
+		"data": nil,
+	})
+}
+
+// GetRolloutStatus 获取deployment的发布进度
+// wait为true时阻塞直到发布完成、超过deadline、发布被暂停或超时，stream为true时以SSE推送每次进度变化
+// stream结束时推送done、paused、deadline_exceeded、timeout或error事件
+func (d *deployment) GetRolloutStatus(c *gin.Context) {
+	params := new(struct {
+		DeploymentName string `form:"deployment_name"`
+		Namespace      string `form:"namespace"`
+		Wait           bool   `form:"wait"`
+		Stream         bool   `form:"stream"`
+		Timeout        int    `form:"timeout"`
+		Cluster        string `form:"cluster"`
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	if !params.Wait && !params.Stream {
+		data, err := service.Deployment.GetRolloutStatus(client, params.Namespace, params.DeploymentName)
+		if err != nil {
+			logger.Error("获取deployment发布进度失败," + err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"msg":  err.Error(),
+				"data": nil,
+			})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"msg":  "获取deployment发布进度成功",
+			"data": data,
+		})
+		return
+	}
+
+	timeout := params.Timeout
+	if timeout <= 0 || timeout > config.RolloutStatusTimeout {
+		timeout = config.RolloutStatusTimeout
+	}
+	// 客户端断开连接时request的context会被取消，监听随之结束
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	if params.Stream {
+		c.Header("Content-Type", "text/event-stream")
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		data, err := service.Deployment.WaitRolloutStatus(ctx, client, params.Namespace, params.DeploymentName, func(status *service.RolloutStatus) {
+			c.SSEvent("status", status)
+			c.Writer.Flush()
+		})
+		// 结束事件与停止监听的原因一一对应，客户端可以根据事件名区分
+		switch {
+		case errors.Is(err, context.DeadlineExceeded):
+			c.SSEvent("timeout", data)
+		case err != nil:
+			c.SSEvent("error", err.Error())
+		case data.Paused:
+			c.SSEvent("paused", data)
+		case data.DeadlineExceeded:
+			c.SSEvent("deadline_exceeded", data)
+		default:
+			c.SSEvent("done", data)
+		}
+		c.Writer.Flush()
+		return
+	}
+
+	data, err := service.Deployment.WaitRolloutStatus(ctx, client, params.Namespace, params.DeploymentName, nil)
+	if errors.Is(err, context.DeadlineExceeded) {
+		err = errors.New("等待deployment发布完成超时")
+	}
+	if err != nil {
+		logger.Error("等待deployment发布完成失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": data,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  data.Message,
+		"data": data,
+	})
+}
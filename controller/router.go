@@ -32,6 +32,14 @@ func (*router) InitApiRouter(r *gin.Engine) {
 		podGroup.PUT("/pod/update", Pod.UpdatePod)
 		podGroup.GET("/pod/container", Pod.GetPodContainer)
 		podGroup.GET("/pod/log", Pod.GetPodLog)
+		podGroup.GET("/pod/log/follow", Pod.FollowPodLog)
+		podGroup.GET("/pod/log/workload", Pod.FollowWorkloadLog)
+		podGroup.GET("/pod/log/download", Pod.DownloadPodLog)
+		podGroup.GET("/pod/log/search", Pod.SearchPodLog)
+		podGroup.GET("/pod/exec", Terminal.ExecPod)
+		podGroup.GET("/pod/file", PodFile.ListFiles)
+		podGroup.GET("/pod/file/download", PodFile.DownloadFile)
+		podGroup.POST("/pod/file/upload", PodFile.UploadFile)
 	}
 	// Deployment 路由服务
 	deploymentGroup := r.Group(apiBasePath)
@@ -44,5 +52,197 @@ func (*router) InitApiRouter(r *gin.Engine) {
 		deploymentGroup.PUT("/deployment/update", Deployment.UpdateDeployment)
 		deploymentGroup.GET("/deployment/numnp", Deployment.GetDeployNumPerNp)
 		deploymentGroup.POST("/deployment/create", Deployment.CreateDeployment)
+		deploymentGroup.GET("/deployment/history", Deployment.GetDeploymentHistory)
+		deploymentGroup.GET("/deployment/diff", Deployment.DiffDeploymentRevisions)
+		deploymentGroup.PUT("/deployment/rollback", Deployment.RollbackDeployment)
+		deploymentGroup.GET("/deployment/rollout/status", Deployment.GetRolloutStatus)
+		deploymentGroup.PUT("/deployment/pause", Deployment.PauseDeployment)
+		deploymentGroup.PUT("/deployment/resume", Deployment.ResumeDeployment)
+	}
+	// StatefulSet 路由服务
+	statefulSetGroup := r.Group(apiBasePath)
+	{
+		statefulSetGroup.GET("/statefulset", StatefulSet.GetStatefulSets)
+		statefulSetGroup.GET("/statefulset/detail", StatefulSet.GetStatefulSetDetail)
+		statefulSetGroup.PUT("/statefulset/scale", StatefulSet.ScaleStatefulSet)
+		statefulSetGroup.DELETE("/statefulset/del", StatefulSet.DeleteStatefulSet)
+		statefulSetGroup.PUT("/statefulset/restart", StatefulSet.RestartStatefulSet)
+		statefulSetGroup.PUT("/statefulset/update", StatefulSet.UpdateStatefulSet)
+		statefulSetGroup.POST("/statefulset/create", StatefulSet.CreateStatefulSet)
+		statefulSetGroup.GET("/statefulset/pods", StatefulSet.GetStatefulSetPods)
+		statefulSetGroup.PUT("/statefulset/partition", StatefulSet.UpdateStatefulSetPartition)
+	}
+	// DaemonSet 路由服务
+	daemonSetGroup := r.Group(apiBasePath)
+	{
+		daemonSetGroup.GET("/daemonset", DaemonSet.GetDaemonSets)
+		daemonSetGroup.GET("/daemonset/detail", DaemonSet.GetDaemonSetDetail)
+		daemonSetGroup.DELETE("/daemonset/del", DaemonSet.DeleteDaemonSet)
+		daemonSetGroup.PUT("/daemonset/restart", DaemonSet.RestartDaemonSet)
+		daemonSetGroup.PUT("/daemonset/update", DaemonSet.UpdateDaemonSet)
+		daemonSetGroup.GET("/daemonset/nodes", DaemonSet.GetDaemonSetNodeStatus)
+	}
+	// Service 路由服务
+	serviceGroup := r.Group(apiBasePath)
+	{
+		serviceGroup.GET("/service", Servicev1.GetServices)
+		serviceGroup.GET("/service/detail", Servicev1.GetServiceDetail)
+		serviceGroup.POST("/service/create", Servicev1.CreateService)
+		serviceGroup.DELETE("/service/del", Servicev1.DeleteService)
+		serviceGroup.PUT("/service/update", Servicev1.UpdateService)
+	}
+	// Ingress 路由服务
+	ingressGroup := r.Group(apiBasePath)
+	{
+		ingressGroup.GET("/ingress", Ingress.GetIngresses)
+		ingressGroup.GET("/ingress/detail", Ingress.GetIngressDetail)
+		ingressGroup.POST("/ingress/create", Ingress.CreateIngress)
+		ingressGroup.DELETE("/ingress/del", Ingress.DeleteIngress)
+		ingressGroup.PUT("/ingress/update", Ingress.UpdateIngress)
+		ingressGroup.GET("/ingress/routes", Ingress.GetIngressRoutes)
+	}
+	// ConfigMap 路由服务
+	configMapGroup := r.Group(apiBasePath)
+	{
+		configMapGroup.GET("/configmap", ConfigMap.GetConfigMaps)
+		configMapGroup.GET("/configmap/detail", ConfigMap.GetConfigMapDetail)
+		configMapGroup.POST("/configmap/create", ConfigMap.CreateConfigMap)
+		configMapGroup.DELETE("/configmap/del", ConfigMap.DeleteConfigMap)
+		configMapGroup.PUT("/configmap/update", ConfigMap.UpdateConfigMap)
+	}
+	// Secret 路由服务
+	secretGroup := r.Group(apiBasePath)
+	{
+		secretGroup.GET("/secret", Secret.GetSecrets)
+		secretGroup.GET("/secret/detail", Secret.GetSecretDetail)
+		secretGroup.GET("/secret/reveal", Secret.RevealSecret)
+		secretGroup.POST("/secret/create", Secret.CreateSecret)
+		secretGroup.DELETE("/secret/del", Secret.DeleteSecret)
+		secretGroup.PUT("/secret/update", Secret.UpdateSecret)
+	}
+	// Node 路由服务
+	nodeGroup := r.Group(apiBasePath)
+	{
+		nodeGroup.GET("/node", Node.GetNodes)
+		nodeGroup.GET("/node/detail", Node.GetNodeDetail)
+		nodeGroup.PUT("/node/cordon", Node.CordonNode)
+		nodeGroup.PUT("/node/uncordon", Node.UncordonNode)
+		nodeGroup.PUT("/node/drain", Node.DrainNode)
+	}
+	// Namespace 路由服务
+	namespaceGroup := r.Group(apiBasePath)
+	{
+		namespaceGroup.GET("/namespace", Namespace.GetNamespaces)
+		namespaceGroup.GET("/namespace/templates", Namespace.GetNamespaceTemplates)
+		namespaceGroup.POST("/namespace/create", Namespace.CreateNamespace)
+		namespaceGroup.DELETE("/namespace/del", Namespace.DeleteNamespace)
+	}
+	// Job 路由服务
+	jobGroup := r.Group(apiBasePath)
+	{
+		jobGroup.GET("/job", Job.GetJobs)
+		jobGroup.GET("/job/detail", Job.GetJobDetail)
+		jobGroup.DELETE("/job/del", Job.DeleteJob)
+	}
+	// CronJob 路由服务
+	cronJobGroup := r.Group(apiBasePath)
+	{
+		cronJobGroup.GET("/cronjob", CronJob.GetCronJobs)
+		cronJobGroup.GET("/cronjob/detail", CronJob.GetCronJobDetail)
+		cronJobGroup.DELETE("/cronjob/del", CronJob.DeleteCronJob)
+		cronJobGroup.PUT("/cronjob/suspend", CronJob.SuspendCronJob)
+		cronJobGroup.PUT("/cronjob/resume", CronJob.ResumeCronJob)
+		cronJobGroup.POST("/cronjob/trigger", CronJob.TriggerCronJob)
+		cronJobGroup.GET("/cronjob/history", CronJob.GetCronJobHistory)
+	}
+	// Event 路由服务
+	eventGroup := r.Group(apiBasePath)
+	{
+		eventGroup.GET("/event", Event.GetEvents)
+	}
+	// PVC 路由服务
+	pvcGroup := r.Group(apiBasePath)
+	{
+		pvcGroup.GET("/pvc", Pvc.GetPvcs)
+		pvcGroup.GET("/pvc/detail", Pvc.GetPvcDetail)
+		pvcGroup.POST("/pvc/create", Pvc.CreatePvc)
+		pvcGroup.DELETE("/pvc/del", Pvc.DeletePvc)
+		pvcGroup.GET("/pvc/usage", Pvc.GetPvcUsage)
+	}
+	// PV 路由服务
+	pvGroup := r.Group(apiBasePath)
+	{
+		pvGroup.GET("/pv", Pv.GetPvs)
+		pvGroup.GET("/pv/detail", Pv.GetPvDetail)
+		pvGroup.DELETE("/pv/del", Pv.DeletePv)
+	}
+	// StorageClass 路由服务
+	storageClassGroup := r.Group(apiBasePath)
+	{
+		storageClassGroup.GET("/storageclass", StorageClass.GetStorageClasses)
+		storageClassGroup.GET("/storageclass/detail", StorageClass.GetStorageClassDetail)
+		storageClassGroup.DELETE("/storageclass/del", StorageClass.DeleteStorageClass)
+	}
+	// HPA 路由服务
+	hpaGroup := r.Group(apiBasePath)
+	{
+		hpaGroup.GET("/hpa", Hpa.GetHpas)
+		hpaGroup.GET("/hpa/detail", Hpa.GetHpaDetail)
+		hpaGroup.POST("/hpa/create", Hpa.CreateHpa)
+		hpaGroup.PUT("/hpa/update", Hpa.UpdateHpa)
+		hpaGroup.DELETE("/hpa/del", Hpa.DeleteHpa)
+	}
+	// RBAC 路由服务
+	rbacGroup := r.Group(apiBasePath)
+	{
+		rbacGroup.GET("/serviceaccount", ServiceAccount.GetServiceAccounts)
+		rbacGroup.GET("/serviceaccount/detail", ServiceAccount.GetServiceAccountDetail)
+		rbacGroup.GET("/role", Role.GetRoles)
+		rbacGroup.GET("/role/detail", Role.GetRoleDetail)
+		rbacGroup.GET("/clusterrole", ClusterRole.GetClusterRoles)
+		rbacGroup.GET("/clusterrole/detail", ClusterRole.GetClusterRoleDetail)
+		rbacGroup.GET("/rolebinding", RoleBinding.GetRoleBindings)
+		rbacGroup.GET("/rolebinding/detail", RoleBinding.GetRoleBindingDetail)
+		rbacGroup.GET("/clusterrolebinding", ClusterRoleBinding.GetClusterRoleBindings)
+		rbacGroup.GET("/clusterrolebinding/detail", ClusterRoleBinding.GetClusterRoleBindingDetail)
+		rbacGroup.GET("/rbac/whocan", Rbac.WhoCan)
+	}
+	// NetworkPolicy 路由服务
+	networkPolicyGroup := r.Group(apiBasePath)
+	{
+		networkPolicyGroup.GET("/networkpolicy", NetworkPolicy.GetNetworkPolicies)
+		networkPolicyGroup.GET("/networkpolicy/detail", NetworkPolicy.GetNetworkPolicyDetail)
+		networkPolicyGroup.POST("/networkpolicy/create", NetworkPolicy.CreateNetworkPolicy)
+		networkPolicyGroup.PUT("/networkpolicy/update", NetworkPolicy.UpdateNetworkPolicy)
+		networkPolicyGroup.DELETE("/networkpolicy/del", NetworkPolicy.DeleteNetworkPolicy)
+		networkPolicyGroup.GET("/networkpolicy/reachability", NetworkPolicy.EvaluateReachability)
+	}
+	// 通用资源 路由服务，group为core时表示core组，例如 /resources/core/v1/pods
+	dynamicResourceGroup := r.Group(apiBasePath)
+	{
+		dynamicResourceGroup.GET("/resources", DynamicResource.GetApiResources)
+		dynamicResourceGroup.GET("/resources/:group/:version/:resource", DynamicResource.GetResources)
+		dynamicResourceGroup.GET("/resources/:group/:version/:resource/:name", DynamicResource.GetResourceDetail)
+		dynamicResourceGroup.POST("/resources/:group/:version/:resource", DynamicResource.CreateResource)
+		dynamicResourceGroup.PATCH("/resources/:group/:version/:resource/:name", DynamicResource.PatchResource)
+		dynamicResourceGroup.DELETE("/resources/:group/:version/:resource/:name", DynamicResource.DeleteResource)
+	}
+	// CRD 路由服务
+	crdGroup := r.Group(apiBasePath)
+	{
+		crdGroup.GET("/crd", Crd.GetCrds)
+		crdGroup.GET("/crd/detail", Crd.GetCrdDetail)
+		crdGroup.GET("/crd/resources", Crd.GetCustomResources)
+	}
+	// ReplicaSet 路由服务
+	replicaSetGroup := r.Group(apiBasePath)
+	{
+		replicaSetGroup.GET("/replicaset", ReplicaSet.GetReplicaSets)
+		replicaSetGroup.GET("/replicaset/detail", ReplicaSet.GetReplicaSetDetail)
+	}
+	// Workload 路由服务，kind为deployment、statefulset或daemonset
+	workloadGroup := r.Group(apiBasePath)
+	{
+		workloadGroup.PUT("/workload/setimage", Workload.SetImage)
 	}
 }
@@ -0,0 +1,255 @@
+package controller
+
+import (
+	"kubea-go/service"
+	"net/http"
+
+	"github.com/aryming/logger"
+	"github.com/gin-gonic/gin"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  secret.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-04-11 15:20
+ */
+
+var Secret secret
+
+type secret struct{}
+
+// GetSecrets 获取secret列表，支持过滤、排序、分页，值已掩码
+func (s *secret) GetSecrets(c *gin.Context) {
+	params := new(struct {
+		FilterName string `form:"filter_name"`
+		Namespace  string `form:"namespace"`
+		Page       int    `form:"page"`
+		Limit      int    `form:"limit"`
+		Cluster    string `form:"cluster"`
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.Secret.GetSecrets(client, params.FilterName, params.Namespace, params.Limit, params.Page)
+	if err != nil {
+		logger.Error("获取secret列表失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "获取secret列表成功",
+		"data": data,
+	})
+}
+
+// GetSecretDetail 获取secret详情，值已掩码
+func (s *secret) GetSecretDetail(c *gin.Context) {
+	params := new(struct {
+		SecretName string `form:"secret_name"`
+		Namespace  string `form:"namespace"`
+		Cluster    string `form:"cluster"`
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.Secret.GetSecretDetail(client, params.Namespace, params.SecretName)
+	if err != nil {
+		logger.Error("获取secret详情失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "获取secret详情成功",
+		"data": data,
+	})
+}
+
+// RevealSecret 获取secret的明文值，key为空时返回全部key
+func (s *secret) RevealSecret(c *gin.Context) {
+	params := new(struct {
+		SecretName string `form:"secret_name"`
+		Namespace  string `form:"namespace"`
+		Key        string `form:"key"`
+		Cluster    string `form:"cluster"`
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.Secret.RevealSecret(client, params.Namespace, params.SecretName, params.Key)
+	if err != nil {
+		logger.Error("获取secret明文失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "获取secret明文成功",
+		"data": data,
+	})
+}
+
+// CreateSecret 创建secret
+func (s *secret) CreateSecret(c *gin.Context) {
+	var (
+		secretCreate = new(service.SecretCreate)
+		err          error
+	)
+	if err = c.ShouldBindJSON(secretCreate); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(secretCreate.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	if err := service.Secret.CreateSecret(client, secretCreate); err != nil {
+		logger.Error("创建secret失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "创建secret成功",
+		"data": nil,
+	})
+}
+
+// DeleteSecret 删除secret
+func (s *secret) DeleteSecret(c *gin.Context) {
+	params := new(struct {
+		SecretName string `json:"secret_name"`
+		Namespace  string `json:"namespace"`
+		Cluster    string `json:"cluster"`
+	})
+	if err := c.ShouldBindJSON(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	if err := service.Secret.DeleteSecret(client, params.SecretName, params.Namespace); err != nil {
+		logger.Error("删除secret失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "删除secret成功",
+		"data": nil,
+	})
+}
+
+// UpdateSecret 更新secret，data为明文的key/value
+func (s *secret) UpdateSecret(c *gin.Context) {
+	params := new(struct {
+		SecretName string            `json:"secret_name"`
+		Namespace  string            `json:"namespace"`
+		Data       map[string]string `json:"data"`
+		Cluster    string            `json:"cluster"`
+	})
+	if err := c.ShouldBindJSON(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	if err := service.Secret.UpdateSecret(client, params.Namespace, params.SecretName, params.Data); err != nil {
+		logger.Error("更新secret失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "更新secret成功",
+		"data": nil,
+	})
+}
@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"kubea-go/service"
+	"net/http"
+
+	"github.com/aryming/logger"
+	"github.com/gin-gonic/gin"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  serviceaccount.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-04-25 15:00
+ */
+
+var ServiceAccount serviceAccount
+
+type serviceAccount struct{}
+
+// GetServiceAccounts 获取serviceaccount列表，支持过滤、排序、分页
+func (s *serviceAccount) GetServiceAccounts(c *gin.Context) {
+	params := new(struct {
+		FilterName string `form:"filter_name"`
+		Namespace  string `form:"namespace"`
+		Page       int    `form:"page"`
+		Limit      int    `form:"limit"`
+		Cluster    string `form:"cluster"`
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.ServiceAccount.GetServiceAccounts(client, params.FilterName, params.Namespace, params.Limit, params.Page)
+	if err != nil {
+		logger.Error("获取serviceaccount列表失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "获取serviceaccount列表成功",
+		"data": data,
+	})
+}
+
+// GetServiceAccountDetail 获取serviceaccount详情
+func (s *serviceAccount) GetServiceAccountDetail(c *gin.Context) {
+	params := new(struct {
+		ServiceAccountName string `form:"serviceaccount_name"`
+		Namespace          string `form:"namespace"`
+		Cluster            string `form:"cluster"`
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.ServiceAccount.GetServiceAccountDetail(client, params.Namespace, params.ServiceAccountName)
+	if err != nil {
+		logger.Error("获取serviceaccount详情失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "获取serviceaccount详情成功",
+		"data": data,
+	})
+}
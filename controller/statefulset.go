@@ -0,0 +1,372 @@
+package controller
+
+import (
+	"fmt"
+	"kubea-go/service"
+	"net/http"
+
+	"github.com/aryming/logger"
+	"github.com/gin-gonic/gin"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  statefulset.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-04-02 11:05
+ */
+
+var StatefulSet statefulSet
+
+type statefulSet struct{}
+
+// GetStatefulSets 获取statefulset列表，支持过滤、排序、分页
+func (s *statefulSet) GetStatefulSets(c *gin.Context) {
+	params := new(struct {
+		FilterName string `form:"filter_name"`
+		Namespace  string `form:"namespace"`
+		Page       int    `form:"page"`
+		Limit      int    `form:"limit"`
+		Cluster    string `form:"cluster"`
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.StatefulSet.GetStatefulSets(client, params.FilterName, params.Namespace, params.Limit, params.Page)
+	if err != nil {
+		logger.Error("获取statefulset列表失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "获取statefulset列表成功",
+		"data": data,
+	})
+}
+
+// GetStatefulSetDetail 获取statefulset详情
+func (s *statefulSet) GetStatefulSetDetail(c *gin.Context) {
+	params := new(struct {
+		StatefulSetName string `form:"statefulset_name"`
+		Namespace       string `form:"namespace"`
+		Cluster         string `form:"cluster"`
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.StatefulSet.GetStatefulSetDetail(client, params.Namespace, params.StatefulSetName)
+	if err != nil {
+		logger.Error("获取statefulset详情失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "获取statefulset详情成功",
+		"data": data,
+	})
+}
+
+// CreateStatefulSet 创建statefulset
+func (s *statefulSet) CreateStatefulSet(c *gin.Context) {
+	var (
+		statefulSetCreate = new(service.StatefulSetCreate)
+		err               error
+	)
+	if err = c.ShouldBindJSON(statefulSetCreate); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(statefulSetCreate.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	if err := service.StatefulSet.CreateStatefulSet(client, statefulSetCreate); err != nil {
+		logger.Error("创建statefulset失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "创建statefulset成功",
+		"data": nil,
+	})
+}
+
+// ScaleStatefulSet 设置statefulset副本数
+func (s *statefulSet) ScaleStatefulSet(c *gin.Context) {
+	params := new(struct {
+		StatefulSetName string `json:"statefulset_name"`
+		Namespace       string `json:"namespace"`
+		ScaleNum        int    `json:"scale_num"`
+		Cluster         string `json:"cluster"`
+	})
+	if err := c.ShouldBindJSON(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.StatefulSet.ScaleStatefulSet(client, params.StatefulSetName, params.Namespace, params.ScaleNum)
+	if err != nil {
+		logger.Error("设置statefulset副本数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "设置statefulset副本数成功",
+		"data": fmt.Sprintf("最新副本数: %d", data),
+	})
+}
+
+// DeleteStatefulSet 删除statefulset
+func (s *statefulSet) DeleteStatefulSet(c *gin.Context) {
+	params := new(struct {
+		StatefulSetName string `json:"statefulset_name"`
+		Namespace       string `json:"namespace"`
+		Cluster         string `json:"cluster"`
+	})
+	if err := c.ShouldBindJSON(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	if err := service.StatefulSet.DeleteStatefulSet(client, params.StatefulSetName, params.Namespace); err != nil {
+		logger.Error("删除statefulset失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "删除statefulset成功",
+		"data": nil,
+	})
+}
+
+// RestartStatefulSet 重启statefulset
+func (s *statefulSet) RestartStatefulSet(c *gin.Context) {
+	params := new(struct {
+		StatefulSetName string `json:"statefulset_name"`
+		Namespace       string `json:"namespace"`
+		Cluster         string `json:"cluster"`
+	})
+	if err := c.ShouldBindJSON(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.StatefulSet.RestartStatefulSet(client, params.StatefulSetName, params.Namespace)
+	if err != nil {
+		logger.Error("重启statefulset失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "重启statefulset成功",
+		"data": data,
+	})
+}
+
+// UpdateStatefulSet 更新statefulset
+func (s *statefulSet) UpdateStatefulSet(c *gin.Context) {
+	params := new(struct {
+		Namespace string `json:"namespace"`
+		Content   string `json:"content"`
+		Cluster   string `json:"cluster"`
+	})
+	if err := c.ShouldBindJSON(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	if err := service.StatefulSet.UpdateStatefulSet(client, params.Namespace, params.Content); err != nil {
+		logger.Error("更新statefulset失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "更新statefulset成功",
+		"data": nil,
+	})
+}
+
+// GetStatefulSetPods 获取statefulset下的pod，按序号排列
+func (s *statefulSet) GetStatefulSetPods(c *gin.Context) {
+	params := new(struct {
+		StatefulSetName string `form:"statefulset_name"`
+		Namespace       string `form:"namespace"`
+		Cluster         string `form:"cluster"`
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.StatefulSet.GetStatefulSetPods(client, params.StatefulSetName, params.Namespace)
+	if err != nil {
+		logger.Error("获取statefulset的pod列表失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "获取statefulset的pod列表成功",
+		"data": data,
+	})
+}
+
+// UpdateStatefulSetPartition 设置statefulset滚动更新的分区
+func (s *statefulSet) UpdateStatefulSetPartition(c *gin.Context) {
+	params := new(struct {
+		StatefulSetName string `json:"statefulset_name"`
+		Namespace       string `json:"namespace"`
+		Partition       int32  `json:"partition"`
+		Cluster         string `json:"cluster"`
+	})
+	if err := c.ShouldBindJSON(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	if err := service.StatefulSet.UpdateStatefulSetPartition(client, params.StatefulSetName, params.Namespace, params.Partition); err != nil {
+		logger.Error("设置statefulset分区失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "设置statefulset分区成功",
+		"data": nil,
+	})
+}
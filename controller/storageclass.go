@@ -0,0 +1,136 @@
+package controller
+
+import (
+	"kubea-go/service"
+	"net/http"
+
+	"github.com/aryming/logger"
+	"github.com/gin-gonic/gin"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  storageclass.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-04-23 16:40
+ */
+
+var StorageClass storageClass
+
+type storageClass struct{}
+
+// GetStorageClasses 获取storageclass列表，支持过滤、排序、分页
+func (s *storageClass) GetStorageClasses(c *gin.Context) {
+	params := new(struct {
+		FilterName string `form:"filter_name"`
+		Page       int    `form:"page"`
+		Limit      int    `form:"limit"`
+		Cluster    string `form:"cluster"`
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.StorageClass.GetStorageClasses(client, params.FilterName, params.Limit, params.Page)
+	if err != nil {
+		logger.Error("获取storageclass列表失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "获取storageclass列表成功",
+		"data": data,
+	})
+}
+
+// GetStorageClassDetail 获取storageclass详情
+func (s *storageClass) GetStorageClassDetail(c *gin.Context) {
+	params := new(struct {
+		StorageClassName string `form:"storageclass_name"`
+		Cluster          string `form:"cluster"`
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.StorageClass.GetStorageClassDetail(client, params.StorageClassName)
+	if err != nil {
+		logger.Error("获取storageclass详情失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "获取storageclass详情成功",
+		"data": data,
+	})
+}
+
+// DeleteStorageClass 删除storageclass
+func (s *storageClass) DeleteStorageClass(c *gin.Context) {
+	params := new(struct {
+		StorageClassName string `json:"storageclass_name"`
+		Cluster          string `json:"cluster"`
+	})
+	if err := c.ShouldBindJSON(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	if err := service.StorageClass.DeleteStorageClass(client, params.StorageClassName); err != nil {
+		logger.Error("删除storageclass失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "删除storageclass成功",
+		"data": nil,
+	})
+}
@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"kubea-go/config"
+	"kubea-go/service"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/aryming/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  terminal.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-05-08 10:00
+ */
+
+var Terminal terminal
+
+type terminal struct{}
+
+// upgrader 将http请求升级为websocket连接，只允许同源或config.WebsocketAllowedOrigins中的Origin
+// 浏览器不限制跨站的websocket请求，不校验Origin时任意网页都可以打开容器终端
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin:     checkOrigin,
+}
+
+// checkOrigin 校验websocket请求的Origin，非浏览器客户端不带Origin时允许
+func checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	originURL, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	if strings.EqualFold(originURL.Host, r.Host) {
+		return true
+	}
+	for _, allowed := range strings.Split(config.WebsocketAllowedOrigins, ",") {
+		if strings.EqualFold(strings.TrimSpace(allowed), origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// ExecPod 通过websocket打开容器的交互式终端，消息格式见service.TerminalMessage
+// shell为空时依次尝试bash和sh，shell退出、连接断开或服务关闭时关闭连接
+func (t *terminal) ExecPod(c *gin.Context) {
+	params := new(struct {
+		PodName       string `form:"pod_name"`
+		ContainerName string `form:"container_name"`
+		Namespace     string `form:"namespace"`
+		Shell         string `form:"shell"`
+		Cluster       string `form:"cluster"`
+	})
+	if err := c.Bind(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	restConfig, err := service.K8s.GetRestConfig(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	// 升级失败时upgrader已经向客户端返回了错误
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		logger.Error("升级websocket连接失败," + err.Error())
+		return
+	}
+	session := service.Terminal.NewTerminalSession(conn)
+	err = service.Terminal.ExecPod(c.Request.Context(), client, restConfig, params.Namespace, params.PodName, params.ContainerName,
+		params.Shell, session)
+	if err != nil {
+		// 关闭原因的长度有限制，完整的错误以stdout消息发送
+		_, _ = session.Write([]byte(err.Error() + "\r\n"))
+		session.Close(websocket.CloseInternalServerErr, "执行终端命令失败")
+		return
+	}
+	session.Close(websocket.CloseNormalClosure, "终端已退出")
+}
@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"kubea-go/service"
+	"net/http"
+
+	"github.com/aryming/logger"
+	"github.com/gin-gonic/gin"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  workload.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-05-06 11:00
+ */
+
+var Workload workload
+
+type workload struct{}
+
+// SetImage 修改deployment、statefulset或daemonset中指定容器的镜像，返回修改后的版本
+func (w *workload) SetImage(c *gin.Context) {
+	params := new(struct {
+		Kind        string            `json:"kind"`
+		Name        string            `json:"name"`
+		Namespace   string            `json:"namespace"`
+		Images      map[string]string `json:"images"`
+		ChangeCause string            `json:"change_cause"`
+		Cluster     string            `json:"cluster"`
+	})
+	if err := c.ShouldBindJSON(params); err != nil {
+		logger.Error("Bind请求参数失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	client, err := service.K8s.GetClient(params.Cluster)
+	if err != nil {
+		logger.Error("获取k8s连接失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	data, err := service.Workload.SetImage(client, params.Kind, params.Namespace, params.Name, params.Images, params.ChangeCause)
+	if err != nil {
+		logger.Error("修改镜像失败," + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"msg":  err.Error(),
+			"data": nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg":  "修改镜像成功",
+		"data": data,
+	})
+}
@@ -34,6 +34,8 @@ func main() {
 		Addr:    config.ListenAddress,
 		Handler: r,
 	}
+	// websocket终端连接被hijack，Shutdown不会等待和关闭，需要单独断开
+	srv.RegisterOnShutdown(service.Terminal.Close)
 	go func() {
 		// service connections
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
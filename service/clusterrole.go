@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aryming/logger"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  clusterrole.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-04-25 10:35
+ */
+
+var ClusterRole clusterRole
+
+type clusterRole struct{}
+
+// ClusterRolesResp 定义列表的返回内容，Items是clusterrole元素列表，Total为clusterrole元素数量
+type ClusterRolesResp struct {
+	Items []rbacv1.ClusterRole `json:"items"`
+	Total int                  `json:"total"`
+}
+
+// GetClusterRoles 获取clusterrole列表，支持过滤、排序、分页
+func (c *clusterRole) GetClusterRoles(client *kubernetes.Clientset, filterName string, limit, page int) (*ClusterRolesResp, error) {
+	clusterRoleList, err := client.RbacV1().ClusterRoles().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取ClusterRole列表失败, " + err.Error()))
+		return nil, errors.New("获取ClusterRole列表失败, " + err.Error())
+	}
+	selectableData := &dataSelector{
+		GenericDateSelect: c.toCells(clusterRoleList.Items),
+		dataSelectQuery: &DataSelectQuery{
+			FilterQuery: &FilterQuery{Name: filterName},
+			PaginationQuery: &PaginationQuery{
+				Limit: limit,
+				Page:  page,
+			},
+		},
+	}
+	filtered := selectableData.Filter()
+	total := len(filtered.GenericDateSelect)
+	data := filtered.Sort().Paginate()
+
+	items := c.fromCells(data.GenericDateSelect)
+	return &ClusterRolesResp{
+		Items: items,
+		Total: total,
+	}, nil
+}
+
+// GetClusterRoleDetail 获取clusterrole详情
+func (c *clusterRole) GetClusterRoleDetail(client *kubernetes.Clientset, name string) (*rbacv1.ClusterRole, error) {
+	clusterRole, err := client.RbacV1().ClusterRoles().Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取ClusterRole详情失败, " + err.Error()))
+		return nil, errors.New("获取ClusterRole详情失败, " + err.Error())
+	}
+	return clusterRole, nil
+}
+
+func (c *clusterRole) toCells(std []rbacv1.ClusterRole) []DataCell {
+	cells := make([]DataCell, len(std))
+	for i := range std {
+		cells[i] = clusterRoleCell(std[i])
+	}
+	return cells
+}
+
+func (c *clusterRole) fromCells(cells []DataCell) []rbacv1.ClusterRole {
+	std := make([]rbacv1.ClusterRole, len(cells))
+	for i := range cells {
+		std[i] = rbacv1.ClusterRole(cells[i].(clusterRoleCell))
+	}
+	return std
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aryming/logger"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  clusterrolebinding.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-04-25 11:05
+ */
+
+var ClusterRoleBinding clusterRoleBinding
+
+type clusterRoleBinding struct{}
+
+// ClusterRoleBindingsResp 定义列表的返回内容，Items是clusterrolebinding元素列表，Total为clusterrolebinding元素数量
+type ClusterRoleBindingsResp struct {
+	Items []rbacv1.ClusterRoleBinding `json:"items"`
+	Total int                         `json:"total"`
+}
+
+// GetClusterRoleBindings 获取clusterrolebinding列表，支持过滤、排序、分页
+func (c *clusterRoleBinding) GetClusterRoleBindings(client *kubernetes.Clientset, filterName string, limit, page int) (*ClusterRoleBindingsResp, error) {
+	clusterRoleBindingList, err := client.RbacV1().ClusterRoleBindings().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取ClusterRoleBinding列表失败, " + err.Error()))
+		return nil, errors.New("获取ClusterRoleBinding列表失败, " + err.Error())
+	}
+	selectableData := &dataSelector{
+		GenericDateSelect: c.toCells(clusterRoleBindingList.Items),
+		dataSelectQuery: &DataSelectQuery{
+			FilterQuery: &FilterQuery{Name: filterName},
+			PaginationQuery: &PaginationQuery{
+				Limit: limit,
+				Page:  page,
+			},
+		},
+	}
+	filtered := selectableData.Filter()
+	total := len(filtered.GenericDateSelect)
+	data := filtered.Sort().Paginate()
+
+	items := c.fromCells(data.GenericDateSelect)
+	return &ClusterRoleBindingsResp{
+		Items: items,
+		Total: total,
+	}, nil
+}
+
+// GetClusterRoleBindingDetail 获取clusterrolebinding详情
+func (c *clusterRoleBinding) GetClusterRoleBindingDetail(client *kubernetes.Clientset, name string) (*rbacv1.ClusterRoleBinding, error) {
+	clusterRoleBinding, err := client.RbacV1().ClusterRoleBindings().Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取ClusterRoleBinding详情失败, " + err.Error()))
+		return nil, errors.New("获取ClusterRoleBinding详情失败, " + err.Error())
+	}
+	return clusterRoleBinding, nil
+}
+
+func (c *clusterRoleBinding) toCells(std []rbacv1.ClusterRoleBinding) []DataCell {
+	cells := make([]DataCell, len(std))
+	for i := range std {
+		cells[i] = clusterRoleBindingCell(std[i])
+	}
+	return cells
+}
+
+func (c *clusterRoleBinding) fromCells(cells []DataCell) []rbacv1.ClusterRoleBinding {
+	std := make([]rbacv1.ClusterRoleBinding, len(cells))
+	for i := range cells {
+		std[i] = rbacv1.ClusterRoleBinding(cells[i].(clusterRoleBindingCell))
+	}
+	return std
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/aryming/logger"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  configmap.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-04-11 10:05
+ */
+
+var ConfigMap configMap
+
+type configMap struct{}
+
+// ConfigMapsResp 定义列表的返回内容，Items是configmap元素列表，Total为configmap元素数量
+type ConfigMapsResp struct {
+	Items []corev1.ConfigMap `json:"items"`
+	Total int                `json:"total"`
+}
+
+// ConfigMapCreate 定义创建configmap需要的参数属性
+type ConfigMapCreate struct {
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace"`
+	Labels    map[string]string `json:"labels"`
+	Data      map[string]string `json:"data"`
+	Cluster   string            `json:"cluster"`
+}
+
+// GetConfigMaps 获取configmap列表，支持过滤、排序、分页
+func (c *configMap) GetConfigMaps(client *kubernetes.Clientset, filterName, namespace string, limit, page int) (*ConfigMapsResp, error) {
+	configMapList, err := client.CoreV1().ConfigMaps(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取ConfigMap列表失败, " + err.Error()))
+		return nil, errors.New("获取ConfigMap列表失败, " + err.Error())
+	}
+	selectableData := &dataSelector{
+		GenericDateSelect: c.toCells(configMapList.Items),
+		dataSelectQuery: &DataSelectQuery{
+			FilterQuery: &FilterQuery{Name: filterName},
+			PaginationQuery: &PaginationQuery{
+				Limit: limit,
+				Page:  page,
+			},
+		},
+	}
+	filtered := selectableData.Filter()
+	total := len(filtered.GenericDateSelect)
+	data := filtered.Sort().Paginate()
+
+	configMaps := c.fromCells(data.GenericDateSelect)
+	return &ConfigMapsResp{
+		Items: configMaps,
+		Total: total,
+	}, nil
+}
+
+// GetConfigMapDetail 获取configmap详情
+func (c *configMap) GetConfigMapDetail(client *kubernetes.Clientset, namespace, name string) (*corev1.ConfigMap, error) {
+	configMap, err := client.CoreV1().ConfigMaps(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取ConfigMap详情失败, " + err.Error()))
+		return nil, errors.New("获取ConfigMap详情失败, " + err.Error())
+	}
+	return configMap, nil
+}
+
+// CreateConfigMap 创建configmap,接收ConfigMapCreate对象
+func (c *configMap) CreateConfigMap(client *kubernetes.Clientset, data *ConfigMapCreate) (err error) {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      data.Name,
+			Namespace: data.Namespace,
+			Labels:    data.Labels,
+		},
+		Data: data.Data,
+	}
+	_, err = client.CoreV1().ConfigMaps(data.Namespace).Create(context.TODO(), configMap, metav1.CreateOptions{})
+	if err != nil {
+		logger.Error(errors.New("创建ConfigMap失败, " + err.Error()))
+		return errors.New("创建ConfigMap失败, " + err.Error())
+	}
+	return nil
+}
+
+// UpdateConfigMap 更新configmap，content参数是请求中传入的configmap对象的json数据
+func (c *configMap) UpdateConfigMap(client *kubernetes.Clientset, namespace, content string) (err error) {
+	var configMap = &corev1.ConfigMap{}
+	err = json.Unmarshal([]byte(content), configMap)
+	if err != nil {
+		logger.Error(errors.New("反序列化失败, " + err.Error()))
+		return errors.New("反序列化失败, " + err.Error())
+	}
+	_, err = client.CoreV1().ConfigMaps(namespace).Update(context.TODO(), configMap, metav1.UpdateOptions{})
+	if err != nil {
+		logger.Error(errors.New("更新ConfigMap失败, " + err.Error()))
+		return errors.New("更新ConfigMap失败, " + err.Error())
+	}
+	return nil
+}
+
+// DeleteConfigMap 删除configmap
+func (c *configMap) DeleteConfigMap(client *kubernetes.Clientset, name, namespace string) (err error) {
+	err = client.CoreV1().ConfigMaps(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+	if err != nil {
+		logger.Error(errors.New("删除ConfigMap失败, " + err.Error()))
+		return errors.New("删除ConfigMap失败, " + err.Error())
+	}
+	return nil
+}
+
+func (c *configMap) toCells(std []corev1.ConfigMap) []DataCell {
+	cells := make([]DataCell, len(std))
+	for i := range std {
+		cells[i] = configMapCell(std[i])
+	}
+	return cells
+}
+
+func (c *configMap) fromCells(cells []DataCell) []corev1.ConfigMap {
+	std := make([]corev1.ConfigMap, len(cells))
+	for i := range cells {
+		std[i] = corev1.ConfigMap(cells[i].(configMapCell))
+	}
+	return std
+}
@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aryming/logger"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  crd.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-04-29 10:00
+ */
+
+var Crd crd
+
+type crd struct{}
+
+// crdResource 为CRD本身的资源类型，通过DynamicClient访问，避免引入apiextensions依赖
+var crdResource = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+// PrinterColumn 定义CRD的additionalPrinterColumns，即kubectl get展示的列
+// Priority大于0的列只在kubectl get -o wide中展示
+type PrinterColumn struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Format      string `json:"format,omitempty"`
+	Description string `json:"description,omitempty"`
+	Priority    int32  `json:"priority,omitempty"`
+	JSONPath    string `json:"jsonPath"`
+}
+
+// CrdSchema 定义CRD版本的schema，OpenAPIV3Schema保持原始结构返回
+type CrdSchema struct {
+	OpenAPIV3Schema map[string]interface{} `json:"openAPIV3Schema"`
+}
+
+// CrdVersion 定义CRD的一个版本，列表中不返回Schema和AdditionalPrinterColumns
+type CrdVersion struct {
+	Name                     string          `json:"name"`
+	Served                   bool            `json:"served"`
+	Storage                  bool            `json:"storage"`
+	Deprecated               bool            `json:"deprecated"`
+	Schema                   *CrdSchema      `json:"schema,omitempty"`
+	AdditionalPrinterColumns []PrinterColumn `json:"additionalPrinterColumns,omitempty"`
+}
+
+// CrdNames 定义CRD的资源名称
+type CrdNames struct {
+	Kind       string   `json:"kind"`
+	Plural     string   `json:"plural"`
+	Singular   string   `json:"singular"`
+	ShortNames []string `json:"shortNames"`
+}
+
+// CrdItem 定义CRD的返回内容，Scope为Namespaced或Cluster
+type CrdItem struct {
+	Name              string        `json:"name"`
+	Group             string        `json:"group"`
+	Names             CrdNames      `json:"names"`
+	Scope             string        `json:"scope"`
+	Versions          []*CrdVersion `json:"versions"`
+	CreationTimestamp metav1.Time   `json:"creation_timestamp"`
+}
+
+// CrdsResp 定义列表的返回内容，Items是CRD元素列表，Total为CRD元素数量
+type CrdsResp struct {
+	Items []*CrdItem `json:"items"`
+	Total int        `json:"total"`
+}
+
+// CustomResourceRow 定义自定义资源列表中的一行，Cells与Columns一一对应
+type CustomResourceRow struct {
+	Name      string        `json:"name"`
+	Namespace string        `json:"namespace"`
+	Cells     []interface{} `json:"cells"`
+}
+
+// CustomResourcesResp 定义自定义资源列表的返回内容，Columns与kubectl get展示的列一致
+type CustomResourcesResp struct {
+	Version string               `json:"version"`
+	Columns []PrinterColumn      `json:"columns"`
+	Rows    []*CustomResourceRow `json:"rows"`
+	Total   int                  `json:"total"`
+}
+
+// crdObject 用于将unstructured格式的CRD转换为结构体
+type crdObject struct {
+	Metadata metav1.ObjectMeta `json:"metadata"`
+	Spec     struct {
+		Group    string        `json:"group"`
+		Names    CrdNames      `json:"names"`
+		Scope    string        `json:"scope"`
+		Versions []*CrdVersion `json:"versions"`
+	} `json:"spec"`
+}
+
+// GetCrds 获取CRD列表，包括版本和作用域，支持过滤、排序、分页
+func (cr *crd) GetCrds(dynamicClient *dynamic.DynamicClient, filterName string, limit, page int) (*CrdsResp, error) {
+	crdList, err := dynamicClient.Resource(crdResource).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取CRD列表失败, " + err.Error()))
+		return nil, errors.New("获取CRD列表失败, " + err.Error())
+	}
+	selectableData := &dataSelector{
+		GenericDateSelect: DynamicResource.toCells(crdList.Items),
+		dataSelectQuery: &DataSelectQuery{
+			FilterQuery: &FilterQuery{Name: filterName},
+			PaginationQuery: &PaginationQuery{
+				Limit: limit,
+				Page:  page,
+			},
+		},
+	}
+	filtered := selectableData.Filter()
+	total := len(filtered.GenericDateSelect)
+	data := filtered.Sort().Paginate()
+
+	items := make([]*CrdItem, 0, len(data.GenericDateSelect))
+	for _, object := range DynamicResource.fromCells(data.GenericDateSelect) {
+		item, err := cr.toCrdItem(&object)
+		if err != nil {
+			return nil, err
+		}
+		// 列表中不返回schema，schema通过详情获取
+		for _, version := range item.Versions {
+			version.Schema = nil
+			version.AdditionalPrinterColumns = nil
+		}
+		items = append(items, item)
+	}
+	return &CrdsResp{
+		Items: items,
+		Total: total,
+	}, nil
+}
+
+// GetCrdDetail 获取CRD详情，包括每个版本的OpenAPI schema和printer columns
+func (cr *crd) GetCrdDetail(dynamicClient *dynamic.DynamicClient, name string) (*CrdItem, error) {
+	object, err := dynamicClient.Resource(crdResource).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取CRD详情失败, " + err.Error()))
+		return nil, errors.New("获取CRD详情失败, " + err.Error())
+	}
+	return cr.toCrdItem(object)
+}
+
+// GetCustomResources 获取CRD对应的自定义资源列表，按CRD的printer columns返回与kubectl get一致的列
+// version为空时使用存储版本，namespace为空时获取所有namespace的资源
+func (cr *crd) GetCustomResources(dynamicClient *dynamic.DynamicClient, crdName, version, filterName, namespace string, limit, page int) (*CustomResourcesResp, error) {
+	item, err := cr.GetCrdDetail(dynamicClient, crdName)
+	if err != nil {
+		return nil, err
+	}
+	crdVersion := cr.getVersion(item, version)
+	if crdVersion == nil {
+		return nil, errors.New("CRD " + crdName + "不存在可用的版本" + version)
+	}
+
+	gvr := schema.GroupVersionResource{Group: item.Group, Version: crdVersion.Name, Resource: item.Names.Plural}
+	var resourceClient dynamic.ResourceInterface = dynamicClient.Resource(gvr)
+	if item.Scope == "Namespaced" && namespace != "" {
+		resourceClient = dynamicClient.Resource(gvr).Namespace(namespace)
+	}
+	list, err := resourceClient.List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取" + item.Names.Kind + "列表失败, " + err.Error()))
+		return nil, errors.New("获取" + item.Names.Kind + "列表失败, " + err.Error())
+	}
+	selectableData := &dataSelector{
+		GenericDateSelect: DynamicResource.toCells(list.Items),
+		dataSelectQuery: &DataSelectQuery{
+			FilterQuery: &FilterQuery{Name: filterName},
+			PaginationQuery: &PaginationQuery{
+				Limit: limit,
+				Page:  page,
+			},
+		},
+	}
+	filtered := selectableData.Filter()
+	total := len(filtered.GenericDateSelect)
+	data := filtered.Sort().Paginate()
+
+	columns := cr.getColumns(crdVersion)
+	rows := make([]*CustomResourceRow, 0, len(data.GenericDateSelect))
+	for _, object := range DynamicResource.fromCells(data.GenericDateSelect) {
+		row := &CustomResourceRow{
+			Name:      object.GetName(),
+			Namespace: object.GetNamespace(),
+			Cells:     make([]interface{}, 0, len(columns)),
+		}
+		for _, column := range columns {
+			row.Cells = append(row.Cells, cr.getCellValue(object.Object, column))
+		}
+		rows = append(rows, row)
+	}
+	return &CustomResourcesResp{
+		Version: crdVersion.Name,
+		Columns: columns,
+		Rows:    rows,
+		Total:   total,
+	}, nil
+}
+
+// toCrdItem 将unstructured格式的CRD转换为CrdItem
+func (cr *crd) toCrdItem(object *unstructured.Unstructured) (*CrdItem, error) {
+	crdObj := &crdObject{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(object.Object, crdObj); err != nil {
+		logger.Error(errors.New("解析CRD失败, " + err.Error()))
+		return nil, errors.New("解析CRD失败, " + err.Error())
+	}
+	return &CrdItem{
+		Name:              crdObj.Metadata.Name,
+		Group:             crdObj.Spec.Group,
+		Names:             crdObj.Spec.Names,
+		Scope:             crdObj.Spec.Scope,
+		Versions:          crdObj.Spec.Versions,
+		CreationTimestamp: crdObj.Metadata.CreationTimestamp,
+	}, nil
+}
+
+// getVersion 获取CRD的指定版本，version为空时返回存储版本
+func (cr *crd) getVersion(item *CrdItem, version string) *CrdVersion {
+	for _, crdVersion := range item.Versions {
+		if !crdVersion.Served {
+			continue
+		}
+		if crdVersion.Name == version || (version == "" && crdVersion.Storage) {
+			return crdVersion
+		}
+	}
+	return nil
+}
+
+// getColumns 获取kubectl get展示的列，第一列总是Name
+// CRD没有定义additionalPrinterColumns时，kubectl默认只展示Age列
+func (cr *crd) getColumns(version *CrdVersion) []PrinterColumn {
+	columns := []PrinterColumn{{
+		Name:     "Name",
+		Type:     "string",
+		Format:   "name",
+		JSONPath: ".metadata.name",
+	}}
+	if len(version.AdditionalPrinterColumns) == 0 {
+		return append(columns, PrinterColumn{
+			Name:     "Age",
+			Type:     "date",
+			JSONPath: ".metadata.creationTimestamp",
+		})
+	}
+	return append(columns, version.AdditionalPrinterColumns...)
+}
+
+// getCellValue 按列的jsonPath取值，字段不存在时返回nil，date类型转换为kubectl风格的时长(例如5d)
+func (cr *crd) getCellValue(object map[string]interface{}, column PrinterColumn) interface{} {
+	parser := jsonpath.New(column.Name).AllowMissingKeys(true)
+	if err := parser.Parse(fmt.Sprintf("{%s}", column.JSONPath)); err != nil {
+		return nil
+	}
+	results, err := parser.FindResults(object)
+	if err != nil || len(results) == 0 || len(results[0]) == 0 {
+		return nil
+	}
+	value := results[0][0].Interface()
+	if column.Type == "date" {
+		str, ok := value.(string)
+		if !ok {
+			return value
+		}
+		timestamp, err := time.Parse(time.RFC3339, str)
+		if err != nil {
+			return value
+		}
+		return duration.HumanDuration(time.Since(timestamp))
+	}
+	return value
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/aryming/logger"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  cronjob.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-04-18 15:30
+ */
+
+var CronJob cronJob
+
+type cronJob struct{}
+
+// CronJobsResp 定义列表的返回内容，Items是cronjob元素列表，Total为cronjob元素数量
+type CronJobsResp struct {
+	Items []batchv1.CronJob `json:"items"`
+	Total int               `json:"total"`
+}
+
+// CronJobHistory 定义cronjob创建的一次job的运行记录，Duration单位为秒，job未结束时为已运行的时长
+type CronJobHistory struct {
+	JobName        string       `json:"job_name"`
+	Status         string       `json:"status"`
+	StartTime      *metav1.Time `json:"start_time"`
+	CompletionTime *metav1.Time `json:"completion_time"`
+	Duration       int64        `json:"duration"`
+	Manual         bool         `json:"manual"`
+}
+
+// GetCronJobs 获取cronjob列表，支持过滤、排序、分页
+func (c *cronJob) GetCronJobs(client *kubernetes.Clientset, filterName, namespace string, limit, page int) (*CronJobsResp, error) {
+	cronJobList, err := client.BatchV1().CronJobs(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取CronJob列表失败, " + err.Error()))
+		return nil, errors.New("获取CronJob列表失败, " + err.Error())
+	}
+	selectableData := &dataSelector{
+		GenericDateSelect: c.toCells(cronJobList.Items),
+		dataSelectQuery: &DataSelectQuery{
+			FilterQuery: &FilterQuery{Name: filterName},
+			PaginationQuery: &PaginationQuery{
+				Limit: limit,
+				Page:  page,
+			},
+		},
+	}
+	filtered := selectableData.Filter()
+	total := len(filtered.GenericDateSelect)
+	data := filtered.Sort().Paginate()
+
+	cronJobs := c.fromCells(data.GenericDateSelect)
+	return &CronJobsResp{
+		Items: cronJobs,
+		Total: total,
+	}, nil
+}
+
+// GetCronJobDetail 获取cronjob详情
+func (c *cronJob) GetCronJobDetail(client *kubernetes.Clientset, namespace, name string) (*batchv1.CronJob, error) {
+	cronJob, err := client.BatchV1().CronJobs(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取CronJob详情失败, " + err.Error()))
+		return nil, errors.New("获取CronJob详情失败, " + err.Error())
+	}
+	return cronJob, nil
+}
+
+// DeleteCronJob 删除cronjob，同时在后台删除cronjob创建的job
+func (c *cronJob) DeleteCronJob(client *kubernetes.Clientset, name, namespace string) (err error) {
+	propagation := metav1.DeletePropagationBackground
+	err = client.BatchV1().CronJobs(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+	if err != nil {
+		logger.Error(errors.New("删除CronJob失败, " + err.Error()))
+		return errors.New("删除CronJob失败, " + err.Error())
+	}
+	return nil
+}
+
+// SuspendCronJob 暂停或恢复cronjob，suspend为true时暂停调度
+func (c *cronJob) SuspendCronJob(client *kubernetes.Clientset, name, namespace string, suspend bool) (err error) {
+	patchData := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"suspend": suspend,
+		},
+	}
+	patchBytes, err := json.Marshal(patchData)
+	if err != nil {
+		logger.Error(errors.New("序列化patchData失败, " + err.Error()))
+		return errors.New("序列化patchData失败, " + err.Error())
+	}
+	_, err = client.BatchV1().CronJobs(namespace).Patch(context.TODO(), name, types.StrategicMergePatchType, patchBytes, metav1.PatchOptions{})
+	if err != nil {
+		logger.Error(errors.New("设置CronJob暂停状态失败, " + err.Error()))
+		return errors.New("设置CronJob暂停状态失败, " + err.Error())
+	}
+	return nil
+}
+
+// TriggerCronJob 立即触发cronjob，等同于kubectl create job --from=cronjob/<name>
+// 使用cronjob的jobTemplate创建job，返回新job的名称
+func (c *cronJob) TriggerCronJob(client *kubernetes.Clientset, name, namespace string) (jobName string, err error) {
+	cronJob, err := c.GetCronJobDetail(client, namespace, name)
+	if err != nil {
+		return "", err
+	}
+	annotations := map[string]string{
+		"cronjob.kubernetes.io/instantiate": "manual",
+	}
+	for key, value := range cronJob.Spec.JobTemplate.Annotations {
+		annotations[key] = value
+	}
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        c.manualJobName(cronJob.Name),
+			Namespace:   namespace,
+			Labels:      cronJob.Spec.JobTemplate.Labels,
+			Annotations: annotations,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(cronJob, batchv1.SchemeGroupVersion.WithKind("CronJob")),
+			},
+		},
+		Spec: cronJob.Spec.JobTemplate.Spec,
+	}
+	newJob, err := client.BatchV1().Jobs(namespace).Create(context.TODO(), job, metav1.CreateOptions{})
+	if err != nil {
+		logger.Error(errors.New("触发CronJob失败, " + err.Error()))
+		return "", errors.New("触发CronJob失败, " + err.Error())
+	}
+	return newJob.Name, nil
+}
+
+// manualJobName 生成手动触发的job名称，格式为<cronjob>-manual-<随机后缀>
+// job名称会作为pod的标签值，长度不能超过63，cronjob名称过长时截断
+func (c *cronJob) manualJobName(cronJobName string) string {
+	const suffixLength = 5
+	maxBaseLength := validation.DNS1123LabelMaxLength - len("-manual-") - suffixLength
+	if len(cronJobName) > maxBaseLength {
+		cronJobName = cronJobName[:maxBaseLength]
+	}
+	return cronJobName + "-manual-" + utilrand.String(suffixLength)
+}
+
+// GetCronJobHistory 获取cronjob创建的job运行记录，按开始时间倒序
+func (c *cronJob) GetCronJobHistory(client *kubernetes.Clientset, name, namespace string) ([]*CronJobHistory, error) {
+	cronJob, err := c.GetCronJobDetail(client, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	jobList, err := client.BatchV1().Jobs(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取Job列表失败, " + err.Error()))
+		return nil, errors.New("获取Job列表失败, " + err.Error())
+	}
+	histories := make([]*CronJobHistory, 0)
+	for i := range jobList.Items {
+		item := &jobList.Items[i]
+		if !metav1.IsControlledBy(item, cronJob) {
+			continue
+		}
+		history := &CronJobHistory{
+			JobName:        item.Name,
+			Status:         Job.getJobStatus(item),
+			StartTime:      item.Status.StartTime,
+			CompletionTime: item.Status.CompletionTime,
+			Manual:         item.Annotations["cronjob.kubernetes.io/instantiate"] == "manual",
+		}
+		if item.Status.StartTime != nil {
+			end := time.Now()
+			if item.Status.CompletionTime != nil {
+				end = item.Status.CompletionTime.Time
+			} else if history.Status == JobStatusFailed {
+				// 失败的job没有completionTime，使用Failed condition的时间
+				for _, condition := range item.Status.Conditions {
+					if condition.Type == batchv1.JobFailed {
+						end = condition.LastTransitionTime.Time
+					}
+				}
+			}
+			history.Duration = int64(end.Sub(item.Status.StartTime.Time).Seconds())
+		}
+		histories = append(histories, history)
+	}
+	sort.Slice(histories, func(i, j int) bool {
+		// 尚未开始的job排在最前
+		if histories[i].StartTime == nil {
+			return histories[j].StartTime != nil
+		}
+		if histories[j].StartTime == nil {
+			return false
+		}
+		return histories[j].StartTime.Before(histories[i].StartTime)
+	})
+	return histories, nil
+}
+
+func (c *cronJob) toCells(std []batchv1.CronJob) []DataCell {
+	cells := make([]DataCell, len(std))
+	for i := range std {
+		cells[i] = cronJobCell(std[i])
+	}
+	return cells
+}
+
+func (c *cronJob) fromCells(cells []DataCell) []batchv1.CronJob {
+	std := make([]batchv1.CronJob, len(cells))
+	for i := range cells {
+		std[i] = batchv1.CronJob(cells[i].(cronJobCell))
+	}
+	return std
+}
@@ -0,0 +1,365 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+
+	"github.com/aryming/logger"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/kubernetes"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  daemonset.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-04-03 14:20
+ */
+
+var DaemonSet daemonSet
+
+type daemonSet struct{}
+
+// DaemonSetsResp 定义列表的返回内容，Items是daemonset元素列表，Total为daemonset元素数量
+type DaemonSetsResp struct {
+	Items []appsv1.DaemonSet `json:"items"`
+	Total int                `json:"total"`
+}
+
+// DaemonSetNodeStatus 定义daemonset在单个node上的发布状态
+// Expected表示按nodeSelector和污点判断该node应该运行daemonset的pod
+// Updated表示pod的controller-revision-hash与daemonset当前的revision一致
+type DaemonSetNodeStatus struct {
+	NodeName  string `json:"node_name"`
+	Expected  bool   `json:"expected"`
+	Scheduled bool   `json:"scheduled"`
+	PodName   string `json:"pod_name"`
+	Ready     bool   `json:"ready"`
+	Updated   bool   `json:"updated"`
+}
+
+// DaemonSetNodeStatusResp 定义daemonset各node发布状态的返回内容
+type DaemonSetNodeStatusResp struct {
+	CurrentRevision string                 `json:"current_revision"`
+	Items           []*DaemonSetNodeStatus `json:"items"`
+}
+
+// GetDaemonSets 获取daemonset列表，支持过滤、排序、分页
+func (d *daemonSet) GetDaemonSets(client *kubernetes.Clientset, filterName, namespace string, limit, page int) (*DaemonSetsResp, error) {
+	daemonSetList, err := client.AppsV1().DaemonSets(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取DaemonSet列表失败, " + err.Error()))
+		return nil, errors.New("获取DaemonSet列表失败, " + err.Error())
+	}
+	selectableData := &dataSelector{
+		GenericDateSelect: d.toCells(daemonSetList.Items),
+		dataSelectQuery: &DataSelectQuery{
+			FilterQuery: &FilterQuery{Name: filterName},
+			PaginationQuery: &PaginationQuery{
+				Limit: limit,
+				Page:  page,
+			},
+		},
+	}
+	filtered := selectableData.Filter()
+	total := len(filtered.GenericDateSelect)
+	data := filtered.Sort().Paginate()
+
+	daemonSets := d.fromCells(data.GenericDateSelect)
+	return &DaemonSetsResp{
+		Items: daemonSets,
+		Total: total,
+	}, nil
+}
+
+// GetDaemonSetDetail 获取daemonset详情
+func (d *daemonSet) GetDaemonSetDetail(client *kubernetes.Clientset, namespace, name string) (*appsv1.DaemonSet, error) {
+	daemonSet, err := client.AppsV1().DaemonSets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取DaemonSet详情失败, " + err.Error()))
+		return nil, errors.New("获取DaemonSet详情失败, " + err.Error())
+	}
+	return daemonSet, nil
+}
+
+// RestartDaemonSet 重启daemonset，等同于kubectl rollout restart，返回重启生成的新版本
+func (d *daemonSet) RestartDaemonSet(client *kubernetes.Clientset, name, namespace string) (*WorkloadRevision, error) {
+	return Workload.Restart(client, WorkloadDaemonSet, namespace, name)
+}
+
+// UpdateDaemonSet 更新daemonset，content参数是请求中传入的daemonset对象的json数据
+func (d *daemonSet) UpdateDaemonSet(client *kubernetes.Clientset, namespace, content string) (err error) {
+	var daemonSet = &appsv1.DaemonSet{}
+	err = json.Unmarshal([]byte(content), daemonSet)
+	if err != nil {
+		logger.Error(errors.New("反序列化失败, " + err.Error()))
+		return errors.New("反序列化失败, " + err.Error())
+	}
+	_, err = client.AppsV1().DaemonSets(namespace).Update(context.TODO(), daemonSet, metav1.UpdateOptions{})
+	if err != nil {
+		logger.Error(errors.New("更新DaemonSet失败, " + err.Error()))
+		return errors.New("更新DaemonSet失败, " + err.Error())
+	}
+	return nil
+}
+
+// DeleteDaemonSet 删除daemonset
+func (d *daemonSet) DeleteDaemonSet(client *kubernetes.Clientset, name, namespace string) (err error) {
+	err = client.AppsV1().DaemonSets(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+	if err != nil {
+		logger.Error(errors.New("删除DaemonSet失败, " + err.Error()))
+		return errors.New("删除DaemonSet失败, " + err.Error())
+	}
+	return nil
+}
+
+// GetDaemonSetNodeStatus 获取daemonset在每个node上的发布状态：是否已调度、是否就绪、是否为当前revision
+func (d *daemonSet) GetDaemonSetNodeStatus(client *kubernetes.Clientset, name, namespace string) (*DaemonSetNodeStatusResp, error) {
+	daemonSet, err := d.GetDaemonSetDetail(client, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	selector, err := metav1.LabelSelectorAsSelector(daemonSet.Spec.Selector)
+	if err != nil {
+		logger.Error(errors.New("解析DaemonSet selector失败, " + err.Error()))
+		return nil, errors.New("解析DaemonSet selector失败, " + err.Error())
+	}
+	nodeList, err := client.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取Node列表失败, " + err.Error()))
+		return nil, errors.New("获取Node列表失败, " + err.Error())
+	}
+	podList, err := client.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		logger.Error(errors.New("获取DaemonSet的Pod列表失败, " + err.Error()))
+		return nil, errors.New("获取DaemonSet的Pod列表失败, " + err.Error())
+	}
+	currentRevision, err := d.getCurrentRevision(client, daemonSet, selector.String())
+	if err != nil {
+		return nil, err
+	}
+
+	// 按node名称索引daemonset的pod
+	podsByNode := make(map[string]*corev1.Pod)
+	for i := range podList.Items {
+		item := &podList.Items[i]
+		if !metav1.IsControlledBy(item, daemonSet) {
+			continue
+		}
+		nodeName := d.getPodNodeName(item)
+		if nodeName == "" {
+			continue
+		}
+		podsByNode[nodeName] = item
+	}
+
+	resp := &DaemonSetNodeStatusResp{
+		CurrentRevision: currentRevision,
+		Items:           make([]*DaemonSetNodeStatus, 0, len(nodeList.Items)),
+	}
+	for i := range nodeList.Items {
+		node := &nodeList.Items[i]
+		status := &DaemonSetNodeStatus{
+			NodeName: node.Name,
+			Expected: d.shouldRunOnNode(daemonSet, node),
+		}
+		if item, ok := podsByNode[node.Name]; ok {
+			status.Scheduled = item.Spec.NodeName != ""
+			status.PodName = item.Name
+			status.Ready = isPodReady(item)
+			status.Updated = currentRevision != "" && item.Labels[appsv1.ControllerRevisionHashLabelKey] == currentRevision
+		}
+		resp.Items = append(resp.Items, status)
+	}
+	sort.Slice(resp.Items, func(i, j int) bool {
+		return resp.Items[i].NodeName < resp.Items[j].NodeName
+	})
+	return resp, nil
+}
+
+// getCurrentRevision 获取daemonset当前的revision hash，即revision号最大的ControllerRevision
+func (d *daemonSet) getCurrentRevision(client *kubernetes.Clientset, daemonSet *appsv1.DaemonSet, selector string) (string, error) {
+	revisionList, err := client.AppsV1().ControllerRevisions(daemonSet.Namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		logger.Error(errors.New("获取DaemonSet的ControllerRevision列表失败, " + err.Error()))
+		return "", errors.New("获取DaemonSet的ControllerRevision列表失败, " + err.Error())
+	}
+	var current *appsv1.ControllerRevision
+	for i := range revisionList.Items {
+		item := &revisionList.Items[i]
+		if !metav1.IsControlledBy(item, daemonSet) {
+			continue
+		}
+		if current == nil || item.Revision > current.Revision {
+			current = item
+		}
+	}
+	if current == nil {
+		return "", nil
+	}
+	return current.Labels[appsv1.ControllerRevisionHashLabelKey], nil
+}
+
+// getPodNodeName 获取pod所在的node，未调度的pod从daemonset设置的节点亲和性中获取目标node
+func (d *daemonSet) getPodNodeName(pod *corev1.Pod) string {
+	if pod.Spec.NodeName != "" {
+		return pod.Spec.NodeName
+	}
+	if pod.Spec.Affinity == nil || pod.Spec.Affinity.NodeAffinity == nil ||
+		pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return ""
+	}
+	for _, term := range pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		for _, field := range term.MatchFields {
+			if field.Key == metav1.ObjectNameField && field.Operator == corev1.NodeSelectorOpIn && len(field.Values) == 1 {
+				return field.Values[0]
+			}
+		}
+	}
+	return ""
+}
+
+// daemonSetDefaultTolerations daemonset controller为每个pod默认添加的容忍，见AddOrUpdateDaemonPodTolerations
+var daemonSetDefaultTolerations = []corev1.Toleration{
+	{Key: corev1.TaintNodeNotReady, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute},
+	{Key: corev1.TaintNodeUnreachable, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute},
+	{Key: corev1.TaintNodeDiskPressure, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+	{Key: corev1.TaintNodeMemoryPressure, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+	{Key: corev1.TaintNodePIDPressure, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+	{Key: corev1.TaintNodeUnschedulable, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+}
+
+// shouldRunOnNode 根据nodeSelector、必须满足的节点亲和性和NoSchedule/NoExecute污点判断node是否应运行daemonset的pod
+// 容忍中包含daemonset controller默认添加的容忍
+func (d *daemonSet) shouldRunOnNode(daemonSet *appsv1.DaemonSet, node *corev1.Node) bool {
+	podSpec := &daemonSet.Spec.Template.Spec
+	if !labels.SelectorFromSet(podSpec.NodeSelector).Matches(labels.Set(node.Labels)) {
+		return false
+	}
+	if podSpec.Affinity != nil && podSpec.Affinity.NodeAffinity != nil &&
+		podSpec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil &&
+		!d.matchNodeSelectorTerms(podSpec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms, node) {
+		return false
+	}
+	tolerations := append(append([]corev1.Toleration{}, podSpec.Tolerations...), daemonSetDefaultTolerations...)
+	// hostNetwork的pod不依赖节点网络插件，controller额外容忍network-unavailable
+	if podSpec.HostNetwork {
+		tolerations = append(tolerations, corev1.Toleration{
+			Key: corev1.TaintNodeNetworkUnavailable, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule,
+		})
+	}
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule {
+			continue
+		}
+		tolerated := false
+		for _, toleration := range tolerations {
+			if toleration.ToleratesTaint(taint) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			return false
+		}
+	}
+	return true
+}
+
+// matchNodeSelectorTerms 判断node是否满足节点亲和性，满足任意一个term即可，与调度器的判断逻辑一致
+// term内的matchExpressions和matchFields需要全部满足，不包含任何条件的term不匹配任何node
+func (d *daemonSet) matchNodeSelectorTerms(terms []corev1.NodeSelectorTerm, node *corev1.Node) bool {
+	for _, term := range terms {
+		if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+			continue
+		}
+		if d.matchNodeSelectorRequirements(term.MatchExpressions, labels.Set(node.Labels)) && d.matchNodeFields(term.MatchFields, node) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchNodeFields 判断node是否满足matchFields，apiserver只允许metadata.name字段和In/NotIn操作
+// node名称可能超过label值的长度限制，不转换为label selector
+func (d *daemonSet) matchNodeFields(requirements []corev1.NodeSelectorRequirement, node *corev1.Node) bool {
+	for _, requirement := range requirements {
+		if requirement.Key != metav1.ObjectNameField {
+			return false
+		}
+		found := false
+		for _, value := range requirement.Values {
+			if value == node.Name {
+				found = true
+				break
+			}
+		}
+		switch requirement.Operator {
+		case corev1.NodeSelectorOpIn:
+			if !found {
+				return false
+			}
+		case corev1.NodeSelectorOpNotIn:
+			if found {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// matchNodeSelectorRequirements 将条件转换为label selector后匹配，条件不合法时视为不匹配
+func (d *daemonSet) matchNodeSelectorRequirements(requirements []corev1.NodeSelectorRequirement, set labels.Set) bool {
+	selector := labels.NewSelector()
+	for _, requirement := range requirements {
+		var op selection.Operator
+		switch requirement.Operator {
+		case corev1.NodeSelectorOpIn:
+			op = selection.In
+		case corev1.NodeSelectorOpNotIn:
+			op = selection.NotIn
+		case corev1.NodeSelectorOpExists:
+			op = selection.Exists
+		case corev1.NodeSelectorOpDoesNotExist:
+			op = selection.DoesNotExist
+		case corev1.NodeSelectorOpGt:
+			op = selection.GreaterThan
+		case corev1.NodeSelectorOpLt:
+			op = selection.LessThan
+		default:
+			return false
+		}
+		r, err := labels.NewRequirement(requirement.Key, op, requirement.Values)
+		if err != nil {
+			return false
+		}
+		selector = selector.Add(*r)
+	}
+	return selector.Matches(set)
+}
+
+func (d *daemonSet) toCells(std []appsv1.DaemonSet) []DataCell {
+	cells := make([]DataCell, len(std))
+	for i := range std {
+		cells[i] = daemonSetCell(std[i])
+	}
+	return cells
+}
+
+func (d *daemonSet) fromCells(cells []DataCell) []appsv1.DaemonSet {
+	std := make([]appsv1.DaemonSet, len(cells))
+	for i := range cells {
+		std[i] = appsv1.DaemonSet(cells[i].(daemonSetCell))
+	}
+	return std
+}
@@ -6,7 +6,13 @@ import (
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 /**
@@ -141,3 +147,238 @@ func (d deploymentCell) GetCreation() time.Time {
 func (d deploymentCell) GetName() string {
 	return d.Name
 }
+
+type statefulSetCell appsv1.StatefulSet
+
+func (s statefulSetCell) GetCreation() time.Time {
+	return s.CreationTimestamp.Time
+}
+
+func (s statefulSetCell) GetName() string {
+	return s.Name
+}
+
+type daemonSetCell appsv1.DaemonSet
+
+func (d daemonSetCell) GetCreation() time.Time {
+	return d.CreationTimestamp.Time
+}
+
+func (d daemonSetCell) GetName() string {
+	return d.Name
+}
+
+type serviceCell corev1.Service
+
+func (s serviceCell) GetCreation() time.Time {
+	return s.CreationTimestamp.Time
+}
+
+func (s serviceCell) GetName() string {
+	return s.Name
+}
+
+type ingressCell networkingv1.Ingress
+
+func (i ingressCell) GetCreation() time.Time {
+	return i.CreationTimestamp.Time
+}
+
+func (i ingressCell) GetName() string {
+	return i.Name
+}
+
+type configMapCell corev1.ConfigMap
+
+func (c configMapCell) GetCreation() time.Time {
+	return c.CreationTimestamp.Time
+}
+
+func (c configMapCell) GetName() string {
+	return c.Name
+}
+
+type secretCell corev1.Secret
+
+func (s secretCell) GetCreation() time.Time {
+	return s.CreationTimestamp.Time
+}
+
+func (s secretCell) GetName() string {
+	return s.Name
+}
+
+type nodeCell corev1.Node
+
+func (n nodeCell) GetCreation() time.Time {
+	return n.CreationTimestamp.Time
+}
+
+func (n nodeCell) GetName() string {
+	return n.Name
+}
+
+type namespaceCell corev1.Namespace
+
+func (n namespaceCell) GetCreation() time.Time {
+	return n.CreationTimestamp.Time
+}
+
+func (n namespaceCell) GetName() string {
+	return n.Name
+}
+
+type jobCell batchv1.Job
+
+func (j jobCell) GetCreation() time.Time {
+	return j.CreationTimestamp.Time
+}
+
+func (j jobCell) GetName() string {
+	return j.Name
+}
+
+type cronJobCell batchv1.CronJob
+
+func (c cronJobCell) GetCreation() time.Time {
+	return c.CreationTimestamp.Time
+}
+
+func (c cronJobCell) GetName() string {
+	return c.Name
+}
+
+// eventCell 按event最近发生的时间排序，按关联对象的名称过滤
+type eventCell corev1.Event
+
+func (e eventCell) GetCreation() time.Time {
+	event := corev1.Event(e)
+	return getEventTime(&event)
+}
+
+func (e eventCell) GetName() string {
+	return e.InvolvedObject.Name
+}
+
+type pvcCell corev1.PersistentVolumeClaim
+
+func (p pvcCell) GetCreation() time.Time {
+	return p.CreationTimestamp.Time
+}
+
+func (p pvcCell) GetName() string {
+	return p.Name
+}
+
+type pvCell corev1.PersistentVolume
+
+func (p pvCell) GetCreation() time.Time {
+	return p.CreationTimestamp.Time
+}
+
+func (p pvCell) GetName() string {
+	return p.Name
+}
+
+type storageClassCell storagev1.StorageClass
+
+func (s storageClassCell) GetCreation() time.Time {
+	return s.CreationTimestamp.Time
+}
+
+func (s storageClassCell) GetName() string {
+	return s.Name
+}
+
+type hpaCell autoscalingv2.HorizontalPodAutoscaler
+
+func (h hpaCell) GetCreation() time.Time {
+	return h.CreationTimestamp.Time
+}
+
+func (h hpaCell) GetName() string {
+	return h.Name
+}
+
+type serviceAccountCell corev1.ServiceAccount
+
+func (s serviceAccountCell) GetCreation() time.Time {
+	return s.CreationTimestamp.Time
+}
+
+func (s serviceAccountCell) GetName() string {
+	return s.Name
+}
+
+type roleCell rbacv1.Role
+
+func (r roleCell) GetCreation() time.Time {
+	return r.CreationTimestamp.Time
+}
+
+func (r roleCell) GetName() string {
+	return r.Name
+}
+
+type clusterRoleCell rbacv1.ClusterRole
+
+func (c clusterRoleCell) GetCreation() time.Time {
+	return c.CreationTimestamp.Time
+}
+
+func (c clusterRoleCell) GetName() string {
+	return c.Name
+}
+
+type roleBindingCell rbacv1.RoleBinding
+
+func (r roleBindingCell) GetCreation() time.Time {
+	return r.CreationTimestamp.Time
+}
+
+func (r roleBindingCell) GetName() string {
+	return r.Name
+}
+
+type clusterRoleBindingCell rbacv1.ClusterRoleBinding
+
+func (c clusterRoleBindingCell) GetCreation() time.Time {
+	return c.CreationTimestamp.Time
+}
+
+func (c clusterRoleBindingCell) GetName() string {
+	return c.Name
+}
+
+type networkPolicyCell networkingv1.NetworkPolicy
+
+func (n networkPolicyCell) GetCreation() time.Time {
+	return n.CreationTimestamp.Time
+}
+
+func (n networkPolicyCell) GetName() string {
+	return n.Name
+}
+
+// unstructuredCell 用于dynamic client返回的任意资源对象
+type unstructuredCell unstructured.Unstructured
+
+func (u unstructuredCell) GetCreation() time.Time {
+	object := unstructured.Unstructured(u)
+	return object.GetCreationTimestamp().Time
+}
+
+func (u unstructuredCell) GetName() string {
+	object := unstructured.Unstructured(u)
+	return object.GetName()
+}
+
+type replicaSetCell appsv1.ReplicaSet
+
+func (r replicaSetCell) GetCreation() time.Time {
+	return r.CreationTimestamp.Time
+}
+
+func (r replicaSetCell) GetName() string {
+	return r.Name
+}
@@ -4,12 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"strconv"
-	"time"
-
-	"k8s.io/apimachinery/pkg/api/resource"
-
-	"k8s.io/apimachinery/pkg/util/intstr"
+	"sort"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -17,6 +12,7 @@ import (
 
 	"github.com/aryming/logger"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -39,6 +35,7 @@ type DeploymentsResp struct {
 }
 
 // DeployCreate 定义DeployCreate结构体，用于创建deployment需要的参数属性的定义
+// Image、Cpu、Memory、ContainerPort、HealthCheck、HealthPath为单容器的简单参数，设置了Containers时忽略
 type DeployCreate struct {
 	Name          string            `json:"name"`
 	Namespace     string            `json:"namespace"`
@@ -51,6 +48,16 @@ type DeployCreate struct {
 	HealthCheck   bool              `json:"health_check"`
 	HealthPath    string            `json:"health_path"`
 	Cluster       string            `json:"cluster"`
+	PodSpecCreate
+}
+
+// DeploymentDetail 定义deployment详情的返回内容，内嵌deployment对象，Events为关联的事件
+// Hpa为控制该deployment副本数的hpa，存在hpa时ScaleWarning提示手动设置的副本数会被hpa覆盖
+type DeploymentDetail struct {
+	*appsv1.Deployment
+	Events       []corev1.Event                         `json:"events"`
+	Hpa          *autoscalingv2.HorizontalPodAutoscaler `json:"hpa"`
+	ScaleWarning string                                 `json:"scale_warning,omitempty"`
 }
 
 // DeploysNp 定义DeploysNp类型，用于返回namespace中deployment的数量
@@ -110,7 +117,17 @@ func (d *deployment) ScaleDeployment(client *kubernetes.Clientset, deploymentNam
 }
 
 // CreateDeployment 创建deployment,接收DeployCreate对象
+// Containers为空时使用Image、ContainerPort等简单参数生成一个与deployment同名的容器
 func (d *deployment) CreateDeployment(client *kubernetes.Clientset, deployCreate *DeployCreate) (err error) {
+	podSpecCreate := deployCreate.PodSpecCreate
+	if len(podSpecCreate.Containers) == 0 {
+		podSpecCreate.Containers = []*ContainerCreate{d.toContainerCreate(deployCreate)}
+	}
+	podSpec, err := buildPodSpec(&podSpecCreate)
+	if err != nil {
+		logger.Error(errors.New("创建deployment失败, " + err.Error()))
+		return errors.New("创建deployment失败, " + err.Error())
+	}
 	//	将data中的属性组装成appsv1.Deployment对象
 	deployment := &appsv1.Deployment{
 		//ObjectMeta中定义资源名、命名空间以及标签
@@ -129,73 +146,13 @@ func (d *deployment) CreateDeployment(client *kubernetes.Clientset, deployCreate
 					Name:   deployCreate.Name,
 					Labels: deployCreate.Labels,
 				},
-				Spec: corev1.PodSpec{
-					Containers: []corev1.Container{
-						{
-							Name:  deployCreate.Name,
-							Image: deployCreate.Image,
-							Ports: []corev1.ContainerPort{
-								{
-									Name:          "http",             //容器端口名称
-									Protocol:      corev1.ProtocolTCP, //协议
-									ContainerPort: 80,                 //容器端口
-								},
-							},
-						},
-					},
-				},
+				Spec: podSpec,
 			},
 		},
 		//Status定义资源的运行状态，这里由于是新建，传入空的appsv1.DeploymentStatus{}对象即可
 		Status: appsv1.DeploymentStatus{},
 	}
 
-	//判断是否打开健康检查功能，若打开，则定义ReadinessProbe和LivenessProbe
-	if deployCreate.HealthCheck { //如果打开健康检查
-		//设置第一个容器的ReadinessProbe，因为我们pod中只有一个容器，所以直接使用index 0即可
-		//若pod中有多个容器，则这里需要使用for循环去定义了
-
-		deployment.Spec.Template.Spec.Containers[0].ReadinessProbe = &corev1.Probe{
-			ProbeHandler: corev1.ProbeHandler{
-				HTTPGet: &corev1.HTTPGetAction{
-					Path: deployCreate.HealthPath,
-					Port: intstr.IntOrString{
-						Type:   0,
-						IntVal: deployCreate.ContainerPort,
-					},
-				},
-			},
-			//"#初始化等待时间
-			InitialDelaySeconds: 5,
-			//超时时间
-			TimeoutSeconds: 5,
-			//执行间隔
-			PeriodSeconds: 5,
-		}
-		deployment.Spec.Template.Spec.Containers[0].LivenessProbe = &corev1.Probe{
-			ProbeHandler: corev1.ProbeHandler{
-				HTTPGet: &corev1.HTTPGetAction{
-					Path: deployCreate.HealthPath,
-					Port: intstr.IntOrString{
-						Type:   0,
-						IntVal: deployCreate.ContainerPort,
-					},
-				},
-			},
-			InitialDelaySeconds: 15,
-			TimeoutSeconds:      5,
-			PeriodSeconds:       5,
-		}
-		//定义容器的limit和request资源
-		deployment.Spec.Template.Spec.Containers[0].Resources.Limits = map[corev1.ResourceName]resource.Quantity{
-			corev1.ResourceCPU:    resource.MustParse(deployCreate.Cpu),
-			corev1.ResourceMemory: resource.MustParse(deployCreate.Memory),
-		}
-		deployment.Spec.Template.Spec.Containers[0].Resources.Requests = map[corev1.ResourceName]resource.Quantity{
-			corev1.ResourceCPU:    resource.MustParse(deployCreate.Cpu),
-			corev1.ResourceMemory: resource.MustParse(deployCreate.Memory),
-		}
-	}
 	// 调用sdk创建deployment
 	_, err = client.AppsV1().Deployments(deployment.Namespace).Create(context.TODO(), deployment, metav1.CreateOptions{})
 	if err != nil {
@@ -205,53 +162,74 @@ func (d *deployment) CreateDeployment(client *kubernetes.Clientset, deployCreate
 	return nil
 }
 
-// RestartDeployment 重启deployment
-func (d *deployment) RestartDeployment(client *kubernetes.Clientset, deploymentName, namespace string) (err error) {
-	// 此功能等同于一下kubectl命令
-	//使用patchData Map组装数据
-	patchData := map[string]interface{}{
-		"spec": map[string]interface{}{
-			"template": map[string]interface{}{
-				"spec": map[string]interface{}{
-					"containers": []map[string]interface{}{
-						{
-							"name": deploymentName,
-							"env": []map[string]string{
-								{
-									"name":  "RESTART_",
-									"value": strconv.FormatInt(time.Now().Unix(), 10),
-								},
-							},
-						},
-					},
-				},
-			},
-		},
+// toContainerCreate 将DeployCreate中的简单参数转换为容器定义
+// ContainerPort为0时不设置端口，Cpu、Memory同时作为requests和limits
+func (d *deployment) toContainerCreate(deployCreate *DeployCreate) *ContainerCreate {
+	container := &ContainerCreate{
+		Name:        deployCreate.Name,
+		Image:       deployCreate.Image,
+		HealthCheck: deployCreate.HealthCheck,
+		HealthPath:  deployCreate.HealthPath,
 	}
-	//序列化为字节，因为patch方法只接收字节类型参数
-	patchBytes, err := json.Marshal(patchData)
-
-	if err != nil {
-		logger.Error(errors.New("序列化patchData失败, " + err.Error()))
-		return errors.New("序列化patchData失败, " + err.Error())
+	if deployCreate.ContainerPort > 0 {
+		container.Ports = []*ContainerPortCreate{{
+			Name:          "http",
+			ContainerPort: deployCreate.ContainerPort,
+		}}
 	}
-	//调用patch方法更新deployment
-	_, err = client.AppsV1().Deployments(namespace).Patch(context.TODO(), deploymentName, "application/strategic-merge-patch+json", patchBytes, metav1.PatchOptions{})
-	if err != nil {
-		logger.Error(errors.New("重启deployment失败, " + err.Error()))
-		return errors.New("重启deployment失败, " + err.Error())
+	if deployCreate.Cpu != "" || deployCreate.Memory != "" {
+		resources := &ResourceCreate{Cpu: deployCreate.Cpu, Memory: deployCreate.Memory}
+		container.Requests = resources
+		container.Limits = resources
 	}
-	return nil
+	return container
 }
 
-// GetDeploymentDetail 获取deployment详情
-func (d *deployment) GetDeploymentDetail(client *kubernetes.Clientset, namespace string, name string) (deployment *appsv1.Deployment, err error) {
-	deployment, err = client.AppsV1().Deployments(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+// RestartDeployment 重启deployment，等同于kubectl rollout restart，返回重启生成的新版本
+func (d *deployment) RestartDeployment(client *kubernetes.Clientset, deploymentName, namespace string) (*WorkloadRevision, error) {
+	return Workload.Restart(client, WorkloadDeployment, namespace, deploymentName)
+}
+
+// GetDeploymentDetail 获取deployment详情，同时返回与该deployment及其replicaset关联的事件
+func (d *deployment) GetDeploymentDetail(client *kubernetes.Clientset, namespace string, name string) (detail *DeploymentDetail, err error) {
+	deployment, err := client.AppsV1().Deployments(namespace).Get(context.TODO(), name, metav1.GetOptions{})
 	if err != nil {
 		logger.Error(errors.New("获取deployment详情失败, " + err.Error()))
 		return nil, errors.New("获取deployment详情失败, " + err.Error())
 	}
-	return deployment, nil
+	detail = &DeploymentDetail{Deployment: deployment, Events: []corev1.Event{}}
+	// 事件获取失败不影响详情的返回
+	if events, err := d.getDeploymentEvents(client, deployment); err == nil {
+		detail.Events = events
+	}
+	if hpa, err := Hpa.GetDeploymentHpa(client, namespace, name); err == nil && hpa != nil {
+		detail.Hpa = hpa
+		detail.ScaleWarning = Hpa.ScaleWarning(hpa)
+	}
+	return detail, nil
+}
+
+// getDeploymentEvents 获取deployment及其replicaset的事件，replicaset的事件包含创建pod失败等信息
+func (d *deployment) getDeploymentEvents(client *kubernetes.Clientset, deployment *appsv1.Deployment) ([]corev1.Event, error) {
+	events, err := Event.GetObjectEvents(client, deployment.Namespace, "Deployment", deployment.Name)
+	if err != nil {
+		return nil, err
+	}
+	replicaSets, err := d.getReplicaSets(client, deployment)
+	if err != nil {
+		return nil, err
+	}
+	for i := range replicaSets {
+		replicaSetEvents, err := Event.GetObjectEvents(client, deployment.Namespace, "ReplicaSet", replicaSets[i].Name)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, replicaSetEvents...)
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return getEventTime(&events[j]).Before(getEventTime(&events[i]))
+	})
+	return events, nil
 }
 
 func (d *deployment) DeleteDeployment(client *kubernetes.Clientset, name string, namespace string) (err error) {
@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/aryming/logger"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  dynamicresource.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-04-28 10:00
+ */
+
+var DynamicResource dynamicResource
+
+type dynamicResource struct{}
+
+// CoreGroup 为core组(group为空)在url中的占位名称，例如 /resources/core/v1/pods
+const CoreGroup = "core"
+
+// DynamicResourcesResp 定义列表的返回内容，Items是资源对象列表，Total为资源对象数量
+type DynamicResourcesResp struct {
+	Items []unstructured.Unstructured `json:"items"`
+	Total int                         `json:"total"`
+}
+
+// ApiResource 定义通过discovery获取到的资源类型信息
+type ApiResource struct {
+	Group      string   `json:"group"`
+	Version    string   `json:"version"`
+	Resource   string   `json:"resource"`
+	Kind       string   `json:"kind"`
+	Namespaced bool     `json:"namespaced"`
+	Verbs      []string `json:"verbs"`
+}
+
+// 支持的patch类型，strategic只适用于内置资源
+var patchTypes = map[string]types.PatchType{
+	"merge":     types.MergePatchType,
+	"json":      types.JSONPatchType,
+	"strategic": types.StrategicMergePatchType,
+}
+
+// GetApiResources 通过discovery获取集群支持的资源类型，每个group只返回首选版本，不包括子资源
+func (d *dynamicResource) GetApiResources(client *kubernetes.Clientset) ([]*ApiResource, error) {
+	resourceLists, err := client.Discovery().ServerPreferredResources()
+	// 部分group不可用时(例如metrics-server异常)仍返回其他group的资源
+	if err != nil && len(resourceLists) == 0 {
+		logger.Error(errors.New("获取资源类型失败, " + err.Error()))
+		return nil, errors.New("获取资源类型失败, " + err.Error())
+	}
+	apiResources := make([]*ApiResource, 0)
+	for _, resourceList := range resourceLists {
+		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, item := range resourceList.APIResources {
+			if strings.Contains(item.Name, "/") {
+				continue
+			}
+			apiResources = append(apiResources, &ApiResource{
+				Group:      gv.Group,
+				Version:    gv.Version,
+				Resource:   item.Name,
+				Kind:       item.Kind,
+				Namespaced: item.Namespaced,
+				Verbs:      item.Verbs,
+			})
+		}
+	}
+	sort.Slice(apiResources, func(i, j int) bool {
+		if apiResources[i].Group != apiResources[j].Group {
+			return apiResources[i].Group < apiResources[j].Group
+		}
+		return apiResources[i].Resource < apiResources[j].Resource
+	})
+	return apiResources, nil
+}
+
+// GetResources 获取任意资源类型的对象列表，支持过滤、排序、分页
+// namespace为空时获取所有namespace的对象，集群级资源忽略namespace
+func (d *dynamicResource) GetResources(client *kubernetes.Clientset, dynamicClient *dynamic.DynamicClient, group, version, resource, filterName, namespace string, limit, page int) (*DynamicResourcesResp, error) {
+	resourceClient, err := d.getResourceClient(client, dynamicClient, group, version, resource, namespace, true)
+	if err != nil {
+		return nil, err
+	}
+	list, err := resourceClient.List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取" + resource + "列表失败, " + err.Error()))
+		return nil, errors.New("获取" + resource + "列表失败, " + err.Error())
+	}
+	selectableData := &dataSelector{
+		GenericDateSelect: d.toCells(list.Items),
+		dataSelectQuery: &DataSelectQuery{
+			FilterQuery: &FilterQuery{Name: filterName},
+			PaginationQuery: &PaginationQuery{
+				Limit: limit,
+				Page:  page,
+			},
+		},
+	}
+	filtered := selectableData.Filter()
+	total := len(filtered.GenericDateSelect)
+	data := filtered.Sort().Paginate()
+
+	items := d.fromCells(data.GenericDateSelect)
+	for i := range items {
+		d.maskSecret(group, resource, &items[i])
+	}
+	return &DynamicResourcesResp{
+		Items: items,
+		Total: total,
+	}, nil
+}
+
+// GetResourceDetail 获取任意资源类型的对象详情
+func (d *dynamicResource) GetResourceDetail(client *kubernetes.Clientset, dynamicClient *dynamic.DynamicClient, group, version, resource, namespace, name string) (*unstructured.Unstructured, error) {
+	resourceClient, err := d.getResourceClient(client, dynamicClient, group, version, resource, namespace, false)
+	if err != nil {
+		return nil, err
+	}
+	object, err := resourceClient.Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取" + resource + "详情失败, " + err.Error()))
+		return nil, errors.New("获取" + resource + "详情失败, " + err.Error())
+	}
+	d.maskSecret(group, resource, object)
+	return object, nil
+}
+
+// CreateResource 创建任意资源类型的对象，content为对象的完整json
+// namespace为空时使用对象metadata中的namespace
+func (d *dynamicResource) CreateResource(client *kubernetes.Clientset, dynamicClient *dynamic.DynamicClient, group, version, resource, namespace, content string) (*unstructured.Unstructured, error) {
+	object := &unstructured.Unstructured{}
+	if err := json.Unmarshal([]byte(content), &object.Object); err != nil {
+		logger.Error(errors.New("反序列化失败, " + err.Error()))
+		return nil, errors.New("反序列化失败, " + err.Error())
+	}
+	if namespace == "" {
+		namespace = object.GetNamespace()
+	}
+	resourceClient, err := d.getResourceClient(client, dynamicClient, group, version, resource, namespace, false)
+	if err != nil {
+		return nil, err
+	}
+	created, err := resourceClient.Create(context.TODO(), object, metav1.CreateOptions{})
+	if err != nil {
+		logger.Error(errors.New("创建" + resource + "失败, " + err.Error()))
+		return nil, errors.New("创建" + resource + "失败, " + err.Error())
+	}
+	d.maskSecret(group, resource, created)
+	return created, nil
+}
+
+// PatchResource patch任意资源类型的对象，patchType为merge(默认)、json或strategic
+func (d *dynamicResource) PatchResource(client *kubernetes.Clientset, dynamicClient *dynamic.DynamicClient, group, version, resource, namespace, name, patchType, content string) (*unstructured.Unstructured, error) {
+	if patchType == "" {
+		patchType = "merge"
+	}
+	pt, ok := patchTypes[patchType]
+	if !ok {
+		return nil, errors.New("不支持的patch类型: " + patchType)
+	}
+	resourceClient, err := d.getResourceClient(client, dynamicClient, group, version, resource, namespace, false)
+	if err != nil {
+		return nil, err
+	}
+	patched, err := resourceClient.Patch(context.TODO(), name, pt, []byte(content), metav1.PatchOptions{})
+	if err != nil {
+		logger.Error(errors.New("更新" + resource + "失败, " + err.Error()))
+		return nil, errors.New("更新" + resource + "失败, " + err.Error())
+	}
+	d.maskSecret(group, resource, patched)
+	return patched, nil
+}
+
+// DeleteResource 删除任意资源类型的对象，关联的子对象在后台删除
+func (d *dynamicResource) DeleteResource(client *kubernetes.Clientset, dynamicClient *dynamic.DynamicClient, group, version, resource, namespace, name string) (err error) {
+	resourceClient, err := d.getResourceClient(client, dynamicClient, group, version, resource, namespace, false)
+	if err != nil {
+		return err
+	}
+	propagation := metav1.DeletePropagationBackground
+	err = resourceClient.Delete(context.TODO(), name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+	if err != nil {
+		logger.Error(errors.New("删除" + resource + "失败, " + err.Error()))
+		return errors.New("删除" + resource + "失败, " + err.Error())
+	}
+	return nil
+}
+
+// maskSecret 与Secret详情一致，将secret的data和stringData的值替换为SecretMask，并去除包含明文的last-applied注解
+// 查看明文需要通过secret的reveal接口
+func (d *dynamicResource) maskSecret(group, resource string, object *unstructured.Unstructured) {
+	if group != CoreGroup || resource != "secrets" {
+		return
+	}
+	for _, field := range []string{"data", "stringData"} {
+		values, ok := object.Object[field].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for key := range values {
+			values[key] = SecretMask
+		}
+	}
+	annotations := object.GetAnnotations()
+	if _, ok := annotations[lastAppliedAnnotation]; ok {
+		delete(annotations, lastAppliedAnnotation)
+		object.SetAnnotations(annotations)
+	}
+	object.SetManagedFields(nil)
+}
+
+// getResourceClient 通过discovery确认资源类型存在，并根据是否为namespace级资源返回对应的ResourceInterface
+// allNamespaces为true(列表)时namespace级资源允许namespace为空，表示所有namespace，其他操作必须指定namespace
+func (d *dynamicResource) getResourceClient(client *kubernetes.Clientset, dynamicClient *dynamic.DynamicClient, group, version, resource, namespace string, allNamespaces bool) (dynamic.ResourceInterface, error) {
+	apiResource, err := d.getApiResource(client, group, version, resource)
+	if err != nil {
+		return nil, err
+	}
+	if group == CoreGroup {
+		group = ""
+	}
+	if apiResource.Namespaced && namespace == "" && !allNamespaces {
+		return nil, errors.New(resource + "为namespace级资源, namespace不能为空")
+	}
+	namespaceableClient := dynamicClient.Resource(schema.GroupVersionResource{Group: group, Version: version, Resource: resource})
+	if apiResource.Namespaced && namespace != "" {
+		return namespaceableClient.Namespace(namespace), nil
+	}
+	return namespaceableClient, nil
+}
+
+// getApiResource 通过discovery查找资源类型的定义
+func (d *dynamicResource) getApiResource(client *kubernetes.Clientset, group, version, resource string) (*metav1.APIResource, error) {
+	groupVersion := schema.GroupVersion{Group: group, Version: version}
+	if group == CoreGroup || group == "" {
+		groupVersion.Group = ""
+	}
+	resourceList, err := client.Discovery().ServerResourcesForGroupVersion(groupVersion.String())
+	if err != nil {
+		logger.Error(errors.New("获取资源类型失败, " + err.Error()))
+		return nil, errors.New("获取资源类型失败, " + err.Error())
+	}
+	for i := range resourceList.APIResources {
+		if resourceList.APIResources[i].Name == resource {
+			return &resourceList.APIResources[i], nil
+		}
+	}
+	return nil, errors.New("资源类型" + groupVersion.String() + "/" + resource + "不存在")
+}
+
+func (d *dynamicResource) toCells(std []unstructured.Unstructured) []DataCell {
+	cells := make([]DataCell, len(std))
+	for i := range std {
+		cells[i] = unstructuredCell(std[i])
+	}
+	return cells
+}
+
+func (d *dynamicResource) fromCells(cells []DataCell) []unstructured.Unstructured {
+	std := make([]unstructured.Unstructured, len(cells))
+	for i := range cells {
+		std[i] = unstructured.Unstructured(cells[i].(unstructuredCell))
+	}
+	return std
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/aryming/logger"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  event.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-04-21 10:15
+ */
+
+var Event event
+
+type event struct{}
+
+// EventsResp 定义列表的返回内容，Items是event元素列表，Total为event元素数量
+type EventsResp struct {
+	Items []corev1.Event `json:"items"`
+	Total int            `json:"total"`
+}
+
+// EventFilter 定义event列表的过滤条件，为空的条件不参与过滤
+// Type为Warning或Normal，Kind和Name对应event关联对象(involvedObject)的类型和名称
+type EventFilter struct {
+	Type   string
+	Reason string
+	Kind   string
+	Name   string
+}
+
+// GetEvents 获取event列表，支持按类型、原因、关联对象过滤，按发生时间倒序并分页
+// namespace为空时获取整个集群的event，filterName按关联对象名称模糊匹配
+func (e *event) GetEvents(client *kubernetes.Clientset, filterName, namespace string, filter *EventFilter, limit, page int) (*EventsResp, error) {
+	selector := fields.Set{}
+	if filter.Type != "" {
+		selector["type"] = filter.Type
+	}
+	if filter.Reason != "" {
+		selector["reason"] = filter.Reason
+	}
+	if filter.Kind != "" {
+		selector["involvedObject.kind"] = filter.Kind
+	}
+	if filter.Name != "" {
+		selector["involvedObject.name"] = filter.Name
+	}
+	eventList, err := client.CoreV1().Events(namespace).List(context.TODO(), metav1.ListOptions{
+		FieldSelector: selector.AsSelector().String(),
+	})
+	if err != nil {
+		logger.Error(errors.New("获取Event列表失败, " + err.Error()))
+		return nil, errors.New("获取Event列表失败, " + err.Error())
+	}
+	selectableData := &dataSelector{
+		GenericDateSelect: e.toCells(eventList.Items),
+		dataSelectQuery: &DataSelectQuery{
+			FilterQuery: &FilterQuery{Name: filterName},
+			PaginationQuery: &PaginationQuery{
+				Limit: limit,
+				Page:  page,
+			},
+		},
+	}
+	filtered := selectableData.Filter()
+	total := len(filtered.GenericDateSelect)
+	data := filtered.Sort().Paginate()
+
+	events := e.fromCells(data.GenericDateSelect)
+	return &EventsResp{
+		Items: events,
+		Total: total,
+	}, nil
+}
+
+// GetObjectEvents 获取与指定对象关联的event，按发生时间倒序，用于嵌入到资源详情中
+func (e *event) GetObjectEvents(client *kubernetes.Clientset, namespace, kind, name string) ([]corev1.Event, error) {
+	selector := fields.Set{
+		"involvedObject.kind": kind,
+		"involvedObject.name": name,
+	}
+	eventList, err := client.CoreV1().Events(namespace).List(context.TODO(), metav1.ListOptions{
+		FieldSelector: selector.AsSelector().String(),
+	})
+	if err != nil {
+		logger.Error(errors.New("获取" + kind + "的Event失败, " + err.Error()))
+		return nil, errors.New("获取" + kind + "的Event失败, " + err.Error())
+	}
+	events := eventList.Items
+	sort.Slice(events, func(i, j int) bool {
+		return getEventTime(&events[j]).Before(getEventTime(&events[i]))
+	})
+	return events, nil
+}
+
+// getEventTime 获取event最近一次发生的时间，依次取lastTimestamp、eventTime、creationTimestamp
+func getEventTime(event *corev1.Event) time.Time {
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+	if !event.EventTime.IsZero() {
+		return event.EventTime.Time
+	}
+	return event.CreationTimestamp.Time
+}
+
+func (e *event) toCells(std []corev1.Event) []DataCell {
+	cells := make([]DataCell, len(std))
+	for i := range std {
+		cells[i] = eventCell(std[i])
+	}
+	return cells
+}
+
+func (e *event) fromCells(cells []DataCell) []corev1.Event {
+	std := make([]corev1.Event, len(cells))
+	for i := range cells {
+		std[i] = corev1.Event(cells[i].(eventCell))
+	}
+	return std
+}
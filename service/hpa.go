@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/aryming/logger"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  hpa.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-04-24 10:30
+ */
+
+var Hpa hpa
+
+type hpa struct{}
+
+// HpasResp 定义列表的返回内容，Items是hpa元素列表，Total为hpa元素数量
+type HpasResp struct {
+	Items []autoscalingv2.HorizontalPodAutoscaler `json:"items"`
+	Total int                                     `json:"total"`
+}
+
+// HpaCreate 定义创建hpa需要的参数属性，目标固定为同namespace下的deployment
+// CpuUtilization和MemoryUtilization为目标平均使用率(百分比)，为0时不设置该指标
+type HpaCreate struct {
+	Name              string `json:"name"`
+	Namespace         string `json:"namespace"`
+	Deployment        string `json:"deployment"`
+	MinReplicas       int32  `json:"min_replicas"`
+	MaxReplicas       int32  `json:"max_replicas"`
+	CpuUtilization    int32  `json:"cpu_utilization"`
+	MemoryUtilization int32  `json:"memory_utilization"`
+	Cluster           string `json:"cluster"`
+}
+
+// GetHpas 获取hpa列表，支持过滤、排序、分页
+func (h *hpa) GetHpas(client *kubernetes.Clientset, filterName, namespace string, limit, page int) (*HpasResp, error) {
+	hpaList, err := client.AutoscalingV2().HorizontalPodAutoscalers(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取HPA列表失败, " + err.Error()))
+		return nil, errors.New("获取HPA列表失败, " + err.Error())
+	}
+	selectableData := &dataSelector{
+		GenericDateSelect: h.toCells(hpaList.Items),
+		dataSelectQuery: &DataSelectQuery{
+			FilterQuery: &FilterQuery{Name: filterName},
+			PaginationQuery: &PaginationQuery{
+				Limit: limit,
+				Page:  page,
+			},
+		},
+	}
+	filtered := selectableData.Filter()
+	total := len(filtered.GenericDateSelect)
+	data := filtered.Sort().Paginate()
+
+	hpas := h.fromCells(data.GenericDateSelect)
+	return &HpasResp{
+		Items: hpas,
+		Total: total,
+	}, nil
+}
+
+// GetHpaDetail 获取hpa详情
+func (h *hpa) GetHpaDetail(client *kubernetes.Clientset, namespace, name string) (*autoscalingv2.HorizontalPodAutoscaler, error) {
+	hpa, err := client.AutoscalingV2().HorizontalPodAutoscalers(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取HPA详情失败, " + err.Error()))
+		return nil, errors.New("获取HPA详情失败, " + err.Error())
+	}
+	return hpa, nil
+}
+
+// CreateHpa 创建hpa,接收HpaCreate对象
+func (h *hpa) CreateHpa(client *kubernetes.Clientset, data *HpaCreate) (err error) {
+	if data.MaxReplicas < 1 || (data.MinReplicas > 0 && data.MinReplicas > data.MaxReplicas) {
+		return errors.New("创建HPA失败, 副本数范围不合法")
+	}
+	metrics := make([]autoscalingv2.MetricSpec, 0, 2)
+	if data.CpuUtilization > 0 {
+		metrics = append(metrics, h.resourceMetric(corev1.ResourceCPU, data.CpuUtilization))
+	}
+	if data.MemoryUtilization > 0 {
+		metrics = append(metrics, h.resourceMetric(corev1.ResourceMemory, data.MemoryUtilization))
+	}
+	if len(metrics) == 0 {
+		return errors.New("创建HPA失败, 至少需要设置一个CPU或内存指标")
+	}
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      data.Name,
+			Namespace: data.Namespace,
+		},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       data.Deployment,
+			},
+			MaxReplicas: data.MaxReplicas,
+			Metrics:     metrics,
+		},
+	}
+	if data.MinReplicas > 0 {
+		hpa.Spec.MinReplicas = &data.MinReplicas
+	}
+	_, err = client.AutoscalingV2().HorizontalPodAutoscalers(data.Namespace).Create(context.TODO(), hpa, metav1.CreateOptions{})
+	if err != nil {
+		logger.Error(errors.New("创建HPA失败, " + err.Error()))
+		return errors.New("创建HPA失败, " + err.Error())
+	}
+	return nil
+}
+
+// UpdateHpa 更新hpa，content为hpa的完整json
+func (h *hpa) UpdateHpa(client *kubernetes.Clientset, namespace, content string) (err error) {
+	var hpa = &autoscalingv2.HorizontalPodAutoscaler{}
+	err = json.Unmarshal([]byte(content), hpa)
+	if err != nil {
+		logger.Error(errors.New("反序列化失败, " + err.Error()))
+		return errors.New("反序列化失败, " + err.Error())
+	}
+	_, err = client.AutoscalingV2().HorizontalPodAutoscalers(namespace).Update(context.TODO(), hpa, metav1.UpdateOptions{})
+	if err != nil {
+		logger.Error(errors.New("更新HPA失败, " + err.Error()))
+		return errors.New("更新HPA失败, " + err.Error())
+	}
+	return nil
+}
+
+// DeleteHpa 删除hpa
+func (h *hpa) DeleteHpa(client *kubernetes.Clientset, name, namespace string) (err error) {
+	err = client.AutoscalingV2().HorizontalPodAutoscalers(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+	if err != nil {
+		logger.Error(errors.New("删除HPA失败, " + err.Error()))
+		return errors.New("删除HPA失败, " + err.Error())
+	}
+	return nil
+}
+
+// GetDeploymentHpa 获取控制指定deployment副本数的hpa，没有hpa时返回nil
+func (h *hpa) GetDeploymentHpa(client *kubernetes.Clientset, namespace, deploymentName string) (*autoscalingv2.HorizontalPodAutoscaler, error) {
+	hpaList, err := client.AutoscalingV2().HorizontalPodAutoscalers(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取HPA列表失败, " + err.Error()))
+		return nil, errors.New("获取HPA列表失败, " + err.Error())
+	}
+	for i := range hpaList.Items {
+		target := hpaList.Items[i].Spec.ScaleTargetRef
+		if target.Kind != "Deployment" || target.Name != deploymentName {
+			continue
+		}
+		// 其他api group中同名的Deployment类型(如CRD)不是该deployment，apiVersion为空时视为apps
+		if target.APIVersion != "" {
+			gv, err := schema.ParseGroupVersion(target.APIVersion)
+			if err != nil || gv.Group != appsv1.GroupName {
+				continue
+			}
+		}
+		return &hpaList.Items[i], nil
+	}
+	return nil, nil
+}
+
+// ScaleWarning 生成deployment受hpa控制时的提示信息
+func (h *hpa) ScaleWarning(hpa *autoscalingv2.HorizontalPodAutoscaler) string {
+	return fmt.Sprintf("该deployment的副本数由HPA %s 控制(%d-%d)，手动设置的副本数会被HPA覆盖",
+		hpa.Name, h.getMinReplicas(hpa), hpa.Spec.MaxReplicas)
+}
+
+// getMinReplicas 获取hpa的最小副本数，未设置时默认为1
+func (h *hpa) getMinReplicas(hpa *autoscalingv2.HorizontalPodAutoscaler) int32 {
+	if hpa.Spec.MinReplicas == nil {
+		return 1
+	}
+	return *hpa.Spec.MinReplicas
+}
+
+// resourceMetric 生成按平均使用率扩缩容的资源指标
+func (h *hpa) resourceMetric(name corev1.ResourceName, utilization int32) autoscalingv2.MetricSpec {
+	return autoscalingv2.MetricSpec{
+		Type: autoscalingv2.ResourceMetricSourceType,
+		Resource: &autoscalingv2.ResourceMetricSource{
+			Name: name,
+			Target: autoscalingv2.MetricTarget{
+				Type:               autoscalingv2.UtilizationMetricType,
+				AverageUtilization: &utilization,
+			},
+		},
+	}
+}
+
+func (h *hpa) toCells(std []autoscalingv2.HorizontalPodAutoscaler) []DataCell {
+	cells := make([]DataCell, len(std))
+	for i := range std {
+		cells[i] = hpaCell(std[i])
+	}
+	return cells
+}
+
+func (h *hpa) fromCells(cells []DataCell) []autoscalingv2.HorizontalPodAutoscaler {
+	std := make([]autoscalingv2.HorizontalPodAutoscaler, len(cells))
+	for i := range cells {
+		std[i] = autoscalingv2.HorizontalPodAutoscaler(cells[i].(hpaCell))
+	}
+	return std
+}
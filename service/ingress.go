@@ -0,0 +1,279 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+
+	"github.com/aryming/logger"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  ingress.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-04-09 16:40
+ */
+
+var Ingress ingress
+
+type ingress struct{}
+
+// IngressesResp 定义列表的返回内容，Items是ingress元素列表，Total为ingress元素数量
+type IngressesResp struct {
+	Items []networkingv1.Ingress `json:"items"`
+	Total int                    `json:"total"`
+}
+
+// IngressCreate 定义创建ingress需要的参数属性，Hosts的key为域名，value为该域名下的path列表
+type IngressCreate struct {
+	Name             string                 `json:"name"`
+	Namespace        string                 `json:"namespace"`
+	Labels           map[string]string      `json:"labels"`
+	IngressClassName string                 `json:"ingress_class_name"`
+	Hosts            map[string][]*HttpPath `json:"hosts"`
+	Cluster          string                 `json:"cluster"`
+}
+
+// HttpPath 定义ingress rule中path到后端service的映射
+type HttpPath struct {
+	Path        string                `json:"path"`
+	PathType    networkingv1.PathType `json:"path_type"`
+	ServiceName string                `json:"service_name"`
+	ServicePort int32                 `json:"service_port"`
+}
+
+// IngressRoute 定义路由表中的一行，即 host -> path -> service:port
+// ServiceExists为false表示该规则指向的service在ingress所在namespace中不存在
+// backend为resource类型时Resource为"Kind/名称"，ServiceExists为null表示不适用
+type IngressRoute struct {
+	Namespace     string `json:"namespace"`
+	IngressName   string `json:"ingress_name"`
+	Host          string `json:"host"`
+	Path          string `json:"path"`
+	PathType      string `json:"path_type"`
+	ServiceName   string `json:"service_name"`
+	ServicePort   string `json:"service_port"`
+	Resource      string `json:"resource,omitempty"`
+	ServiceExists *bool  `json:"service_exists"`
+}
+
+// GetIngresses 获取ingress列表，支持过滤、排序、分页
+func (i *ingress) GetIngresses(client *kubernetes.Clientset, filterName, namespace string, limit, page int) (*IngressesResp, error) {
+	ingressList, err := client.NetworkingV1().Ingresses(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取Ingress列表失败, " + err.Error()))
+		return nil, errors.New("获取Ingress列表失败, " + err.Error())
+	}
+	selectableData := &dataSelector{
+		GenericDateSelect: i.toCells(ingressList.Items),
+		dataSelectQuery: &DataSelectQuery{
+			FilterQuery: &FilterQuery{Name: filterName},
+			PaginationQuery: &PaginationQuery{
+				Limit: limit,
+				Page:  page,
+			},
+		},
+	}
+	filtered := selectableData.Filter()
+	total := len(filtered.GenericDateSelect)
+	data := filtered.Sort().Paginate()
+
+	ingresses := i.fromCells(data.GenericDateSelect)
+	return &IngressesResp{
+		Items: ingresses,
+		Total: total,
+	}, nil
+}
+
+// GetIngressDetail 获取ingress详情
+func (i *ingress) GetIngressDetail(client *kubernetes.Clientset, namespace, name string) (*networkingv1.Ingress, error) {
+	ingress, err := client.NetworkingV1().Ingresses(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取Ingress详情失败, " + err.Error()))
+		return nil, errors.New("获取Ingress详情失败, " + err.Error())
+	}
+	return ingress, nil
+}
+
+// CreateIngress 创建ingress,接收IngressCreate对象
+func (i *ingress) CreateIngress(client *kubernetes.Clientset, data *IngressCreate) (err error) {
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      data.Name,
+			Namespace: data.Namespace,
+			Labels:    data.Labels,
+		},
+		Status: networkingv1.IngressStatus{},
+	}
+	if data.IngressClassName != "" {
+		ingress.Spec.IngressClassName = &data.IngressClassName
+	}
+	// 每个host对应一条rule，host下的path组成rule的paths
+	// map的遍历顺序是随机的，按host排序使相同请求生成的rules顺序一致
+	hosts := make([]string, 0, len(data.Hosts))
+	for host := range data.Hosts {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	for _, host := range hosts {
+		paths := data.Hosts[host]
+		rule := networkingv1.IngressRule{
+			Host: host,
+			IngressRuleValue: networkingv1.IngressRuleValue{
+				HTTP: &networkingv1.HTTPIngressRuleValue{},
+			},
+		}
+		for _, httpPath := range paths {
+			pathType := httpPath.PathType
+			if pathType == "" {
+				pathType = networkingv1.PathTypePrefix
+			}
+			rule.HTTP.Paths = append(rule.HTTP.Paths, networkingv1.HTTPIngressPath{
+				Path:     httpPath.Path,
+				PathType: &pathType,
+				Backend: networkingv1.IngressBackend{
+					Service: &networkingv1.IngressServiceBackend{
+						Name: httpPath.ServiceName,
+						Port: networkingv1.ServiceBackendPort{
+							Number: httpPath.ServicePort,
+						},
+					},
+				},
+			})
+		}
+		ingress.Spec.Rules = append(ingress.Spec.Rules, rule)
+	}
+	_, err = client.NetworkingV1().Ingresses(data.Namespace).Create(context.TODO(), ingress, metav1.CreateOptions{})
+	if err != nil {
+		logger.Error(errors.New("创建Ingress失败, " + err.Error()))
+		return errors.New("创建Ingress失败, " + err.Error())
+	}
+	return nil
+}
+
+// UpdateIngress 更新ingress，content参数是请求中传入的ingress对象的json数据
+func (i *ingress) UpdateIngress(client *kubernetes.Clientset, namespace, content string) (err error) {
+	var ingress = &networkingv1.Ingress{}
+	err = json.Unmarshal([]byte(content), ingress)
+	if err != nil {
+		logger.Error(errors.New("反序列化失败, " + err.Error()))
+		return errors.New("反序列化失败, " + err.Error())
+	}
+	_, err = client.NetworkingV1().Ingresses(namespace).Update(context.TODO(), ingress, metav1.UpdateOptions{})
+	if err != nil {
+		logger.Error(errors.New("更新Ingress失败, " + err.Error()))
+		return errors.New("更新Ingress失败, " + err.Error())
+	}
+	return nil
+}
+
+// DeleteIngress 删除ingress
+func (i *ingress) DeleteIngress(client *kubernetes.Clientset, name, namespace string) (err error) {
+	err = client.NetworkingV1().Ingresses(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+	if err != nil {
+		logger.Error(errors.New("删除Ingress失败, " + err.Error()))
+		return errors.New("删除Ingress失败, " + err.Error())
+	}
+	return nil
+}
+
+// GetIngressRoutes 将ingress规则展开为 host -> path -> service:port 的路由表
+// namespace为空时获取整个集群的ingress，并标记指向不存在service的规则
+func (i *ingress) GetIngressRoutes(client *kubernetes.Clientset, namespace string) ([]*IngressRoute, error) {
+	ingressList, err := client.NetworkingV1().Ingresses(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取Ingress列表失败, " + err.Error()))
+		return nil, errors.New("获取Ingress列表失败, " + err.Error())
+	}
+	serviceList, err := client.CoreV1().Services(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取Service列表失败, " + err.Error()))
+		return nil, errors.New("获取Service列表失败, " + err.Error())
+	}
+	// 以"namespace/name"为key记录已存在的service
+	services := make(map[string]bool, len(serviceList.Items))
+	for _, svc := range serviceList.Items {
+		services[svc.Namespace+"/"+svc.Name] = true
+	}
+
+	routes := make([]*IngressRoute, 0)
+	for _, item := range ingressList.Items {
+		// defaultBackend作为host和path都为"*"的路由
+		if item.Spec.DefaultBackend != nil {
+			routes = append(routes, i.toRoute(&item, "*", "*", "", item.Spec.DefaultBackend, services))
+		}
+		for _, rule := range item.Spec.Rules {
+			host := rule.Host
+			if host == "" {
+				host = "*"
+			}
+			if rule.HTTP == nil {
+				continue
+			}
+			for _, path := range rule.HTTP.Paths {
+				pathType := ""
+				if path.PathType != nil {
+					pathType = string(*path.PathType)
+				}
+				backend := path.Backend
+				routes = append(routes, i.toRoute(&item, host, path.Path, pathType, &backend, services))
+			}
+		}
+	}
+	sort.SliceStable(routes, func(a, b int) bool {
+		if routes[a].Host != routes[b].Host {
+			return routes[a].Host < routes[b].Host
+		}
+		return routes[a].Path < routes[b].Path
+	})
+	return routes, nil
+}
+
+// toRoute 将ingress的backend转换为路由表中的一行，resource类型的backend没有service信息，不检查service是否存在
+func (i *ingress) toRoute(item *networkingv1.Ingress, host, path, pathType string, backend *networkingv1.IngressBackend, services map[string]bool) *IngressRoute {
+	route := &IngressRoute{
+		Namespace:   item.Namespace,
+		IngressName: item.Name,
+		Host:        host,
+		Path:        path,
+		PathType:    pathType,
+	}
+	if backend.Service == nil {
+		if backend.Resource != nil {
+			route.Resource = backend.Resource.Kind + "/" + backend.Resource.Name
+		}
+		return route
+	}
+	route.ServiceName = backend.Service.Name
+	if backend.Service.Port.Name != "" {
+		route.ServicePort = backend.Service.Port.Name
+	} else {
+		route.ServicePort = strconv.Itoa(int(backend.Service.Port.Number))
+	}
+	exists := services[item.Namespace+"/"+backend.Service.Name]
+	route.ServiceExists = &exists
+	return route
+}
+
+func (i *ingress) toCells(std []networkingv1.Ingress) []DataCell {
+	cells := make([]DataCell, len(std))
+	for n := range std {
+		cells[n] = ingressCell(std[n])
+	}
+	return cells
+}
+
+func (i *ingress) fromCells(cells []DataCell) []networkingv1.Ingress {
+	std := make([]networkingv1.Ingress, len(cells))
+	for n := range cells {
+		std[n] = networkingv1.Ingress(cells[n].(ingressCell))
+	}
+	return std
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aryming/logger"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  job.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-04-18 14:10
+ */
+
+var Job job
+
+type job struct{}
+
+// job的运行结果
+const (
+	JobStatusRunning   = "running"
+	JobStatusSucceeded = "succeeded"
+	JobStatusFailed    = "failed"
+)
+
+// JobsResp 定义列表的返回内容，Items是job元素列表，Total为job元素数量
+type JobsResp struct {
+	Items []batchv1.Job `json:"items"`
+	Total int           `json:"total"`
+}
+
+// GetJobs 获取job列表，支持过滤、排序、分页
+func (j *job) GetJobs(client *kubernetes.Clientset, filterName, namespace string, limit, page int) (*JobsResp, error) {
+	jobList, err := client.BatchV1().Jobs(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取Job列表失败, " + err.Error()))
+		return nil, errors.New("获取Job列表失败, " + err.Error())
+	}
+	selectableData := &dataSelector{
+		GenericDateSelect: j.toCells(jobList.Items),
+		dataSelectQuery: &DataSelectQuery{
+			FilterQuery: &FilterQuery{Name: filterName},
+			PaginationQuery: &PaginationQuery{
+				Limit: limit,
+				Page:  page,
+			},
+		},
+	}
+	filtered := selectableData.Filter()
+	total := len(filtered.GenericDateSelect)
+	data := filtered.Sort().Paginate()
+
+	jobs := j.fromCells(data.GenericDateSelect)
+	return &JobsResp{
+		Items: jobs,
+		Total: total,
+	}, nil
+}
+
+// GetJobDetail 获取job详情
+func (j *job) GetJobDetail(client *kubernetes.Clientset, namespace, name string) (*batchv1.Job, error) {
+	job, err := client.BatchV1().Jobs(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取Job详情失败, " + err.Error()))
+		return nil, errors.New("获取Job详情失败, " + err.Error())
+	}
+	return job, nil
+}
+
+// DeleteJob 删除job，同时在后台删除job创建的pod
+func (j *job) DeleteJob(client *kubernetes.Clientset, name, namespace string) (err error) {
+	propagation := metav1.DeletePropagationBackground
+	err = client.BatchV1().Jobs(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+	if err != nil {
+		logger.Error(errors.New("删除Job失败, " + err.Error()))
+		return errors.New("删除Job失败, " + err.Error())
+	}
+	return nil
+}
+
+// getJobStatus 根据job的Complete/Failed condition判断job的运行结果
+func (j *job) getJobStatus(job *batchv1.Job) string {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return JobStatusSucceeded
+		case batchv1.JobFailed:
+			return JobStatusFailed
+		}
+	}
+	return JobStatusRunning
+}
+
+func (j *job) toCells(std []batchv1.Job) []DataCell {
+	cells := make([]DataCell, len(std))
+	for i := range std {
+		cells[i] = jobCell(std[i])
+	}
+	return cells
+}
+
+func (j *job) fromCells(cells []DataCell) []batchv1.Job {
+	std := make([]batchv1.Job, len(cells))
+	for i := range cells {
+		std[i] = batchv1.Job(cells[i].(jobCell))
+	}
+	return std
+}
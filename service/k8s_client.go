@@ -14,6 +14,8 @@ import (
 	"kubea-go/config"
 
 	"github.com/aryming/logger"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 
 	"k8s.io/client-go/kubernetes"
@@ -26,6 +28,10 @@ type k8s struct {
 	ClientMap map[string]*kubernetes.Clientset
 	// 提供多集群列表
 	KubeConfMap map[string]string
+	// 提供多集群的rest配置，用于exec等需要直接访问apiserver的场景
+	RestConfigMap map[string]*rest.Config
+	// 提供多集群的DynamicClient，用于操作任意资源类型(包括CRD)
+	DynamicClientMap map[string]*dynamic.DynamicClient
 }
 
 // GetClient 根据集群名称获取Client
@@ -42,12 +48,34 @@ func (k *k8s) GetClient(clusterName string) (*kubernetes.Clientset, error) {
 	return client, nil
 }
 
+// GetDynamicClient 根据集群名称获取DynamicClient
+func (k *k8s) GetDynamicClient(clusterName string) (*dynamic.DynamicClient, error) {
+	client, ok := k.DynamicClientMap[clusterName]
+	if !ok {
+		logger.Error(fmt.Sprintf("集群%s不存在,无法获取DynamicClient\n", clusterName))
+		return nil, errors.New(fmt.Sprintf("集群%s不存在,无法获取DynamicClient\n", clusterName))
+	}
+	return client, nil
+}
+
+// GetRestConfig 根据集群名称获取rest配置
+func (k *k8s) GetRestConfig(clusterName string) (*rest.Config, error) {
+	restConfig, ok := k.RestConfigMap[clusterName]
+	if !ok {
+		logger.Error(fmt.Sprintf("集群%s不存在,无法获取rest配置\n", clusterName))
+		return nil, errors.New(fmt.Sprintf("集群%s不存在,无法获取rest配置\n", clusterName))
+	}
+	return restConfig, nil
+}
+
 // Init 初始化k8s client
 func (k *k8s) Init() {
 	// 创建一个空的map，用于存储Kubeconfigs
 	mp := make(map[string]string, 0)
 	// 创建一个空的map，用于存储Kubernetes的Clientset
 	k.ClientMap = make(map[string]*kubernetes.Clientset, 0)
+	k.RestConfigMap = make(map[string]*rest.Config, 0)
+	k.DynamicClientMap = make(map[string]*dynamic.DynamicClient, 0)
 	// 反序列化
 	if err := json.Unmarshal([]byte(config.Kubeconfigs), &mp); err != nil {
 		// 如果反序列化失败，则抛出异常
@@ -68,8 +96,14 @@ func (k *k8s) Init() {
 			// 如果初始化失败，则抛出异常
 			panic(fmt.Sprintf("初始化集群%s失败,%v\n", key, err))
 		}
+		dynamicClient, err := dynamic.NewForConfig(client)
+		if err != nil {
+			panic(fmt.Sprintf("初始化集群%s失败,%v\n", key, err))
+		}
 		// 将初始化后的Clientset存储到ClientMap中
 		k.ClientMap[key] = clientSet
+		k.RestConfigMap[key] = client
+		k.DynamicClientMap[key] = dynamicClient
 		// 打印初始化成功的日志
 		logger.Info(fmt.Sprintf("初始化集群%s成功", key))
 	}
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"kubea-go/config"
+
+	"github.com/aryming/logger"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  namespace.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-04-16 10:20
+ */
+
+var Namespace namespace
+
+type namespace struct{}
+
+// protectedNamespaces 系统namespace，不允许删除
+var protectedNamespaces = map[string]bool{
+	"default":         true,
+	"kube-system":     true,
+	"kube-public":     true,
+	"kube-node-lease": true,
+}
+
+// NamespacesResp 定义列表的返回内容，Items是namespace元素列表，Total为namespace元素数量
+type NamespacesResp struct {
+	Items []corev1.Namespace `json:"items"`
+	Total int                `json:"total"`
+}
+
+// NamespaceCreate 定义创建namespace需要的参数属性，Template为config.NamespaceTemplates中的模板名称，为空时不创建配额
+type NamespaceCreate struct {
+	Name     string            `json:"name"`
+	Labels   map[string]string `json:"labels"`
+	Template string            `json:"template"`
+	Cluster  string            `json:"cluster"`
+}
+
+// NamespaceTemplate 定义namespace的ResourceQuota和LimitRange模板
+type NamespaceTemplate struct {
+	ResourceQuota map[string]string `json:"resource_quota"`
+	LimitRange    *struct {
+		Default        map[string]string `json:"default"`
+		DefaultRequest map[string]string `json:"default_request"`
+	} `json:"limit_range"`
+}
+
+// NamespaceDeletePreview 定义删除namespace前的资源清单，Resources的key为资源类型，value为资源名称列表
+type NamespaceDeletePreview struct {
+	Namespace string              `json:"namespace"`
+	Resources map[string][]string `json:"resources"`
+	Deleted   bool                `json:"deleted"`
+}
+
+// GetNamespaces 获取namespace列表，支持过滤、排序、分页
+func (n *namespace) GetNamespaces(client *kubernetes.Clientset, filterName string, limit, page int) (*NamespacesResp, error) {
+	namespaceList, err := client.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取Namespace列表失败, " + err.Error()))
+		return nil, errors.New("获取Namespace列表失败, " + err.Error())
+	}
+	selectableData := &dataSelector{
+		GenericDateSelect: n.toCells(namespaceList.Items),
+		dataSelectQuery: &DataSelectQuery{
+			FilterQuery: &FilterQuery{Name: filterName},
+			PaginationQuery: &PaginationQuery{
+				Limit: limit,
+				Page:  page,
+			},
+		},
+	}
+	filtered := selectableData.Filter()
+	total := len(filtered.GenericDateSelect)
+	data := filtered.Sort().Paginate()
+
+	namespaces := n.fromCells(data.GenericDateSelect)
+	return &NamespacesResp{
+		Items: namespaces,
+		Total: total,
+	}, nil
+}
+
+// GetNamespaceTemplates 获取配置中的namespace模板
+func (n *namespace) GetNamespaceTemplates() (map[string]*NamespaceTemplate, error) {
+	templates := make(map[string]*NamespaceTemplate)
+	if err := json.Unmarshal([]byte(config.NamespaceTemplates), &templates); err != nil {
+		logger.Error(errors.New("反序列化NamespaceTemplates失败, " + err.Error()))
+		return nil, errors.New("反序列化NamespaceTemplates失败, " + err.Error())
+	}
+	return templates, nil
+}
+
+// CreateNamespace 创建namespace，Template不为空时同时创建模板中的ResourceQuota和LimitRange
+func (n *namespace) CreateNamespace(client *kubernetes.Clientset, data *NamespaceCreate) (err error) {
+	var template *NamespaceTemplate
+	// 先校验模板，避免namespace创建后才发现模板不存在
+	if data.Template != "" {
+		templates, err := n.GetNamespaceTemplates()
+		if err != nil {
+			return err
+		}
+		var ok bool
+		if template, ok = templates[data.Template]; !ok {
+			return errors.New("namespace模板不存在: " + data.Template)
+		}
+	}
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   data.Name,
+			Labels: data.Labels,
+		},
+	}
+	_, err = client.CoreV1().Namespaces().Create(context.TODO(), namespace, metav1.CreateOptions{})
+	if err != nil {
+		logger.Error(errors.New("创建Namespace失败, " + err.Error()))
+		return errors.New("创建Namespace失败, " + err.Error())
+	}
+	if template == nil {
+		return nil
+	}
+	// 模板中的资源创建失败时删除namespace，避免留下只创建了一半的namespace，重试时报AlreadyExists
+	if err = n.applyTemplate(client, data.Name, data.Template, template); err != nil {
+		if delErr := client.CoreV1().Namespaces().Delete(context.TODO(), data.Name, metav1.DeleteOptions{}); delErr != nil {
+			logger.Error(errors.New("回滚Namespace失败, " + delErr.Error()))
+		}
+		return err
+	}
+	return nil
+}
+
+// applyTemplate 在namespace中创建模板定义的ResourceQuota和LimitRange，资源名称为模板名称
+func (n *namespace) applyTemplate(client *kubernetes.Clientset, namespace, name string, template *NamespaceTemplate) error {
+	if len(template.ResourceQuota) > 0 {
+		hard, err := n.toResourceList(template.ResourceQuota)
+		if err != nil {
+			return err
+		}
+		quota := &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       corev1.ResourceQuotaSpec{Hard: hard},
+		}
+		_, err = client.CoreV1().ResourceQuotas(namespace).Create(context.TODO(), quota, metav1.CreateOptions{})
+		if err != nil {
+			logger.Error(errors.New("创建ResourceQuota失败, " + err.Error()))
+			return errors.New("创建ResourceQuota失败, " + err.Error())
+		}
+	}
+	if template.LimitRange != nil {
+		defaults, err := n.toResourceList(template.LimitRange.Default)
+		if err != nil {
+			return err
+		}
+		defaultRequests, err := n.toResourceList(template.LimitRange.DefaultRequest)
+		if err != nil {
+			return err
+		}
+		limitRange := &corev1.LimitRange{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: corev1.LimitRangeSpec{
+				Limits: []corev1.LimitRangeItem{
+					{
+						Type:           corev1.LimitTypeContainer,
+						Default:        defaults,
+						DefaultRequest: defaultRequests,
+					},
+				},
+			},
+		}
+		_, err = client.CoreV1().LimitRanges(namespace).Create(context.TODO(), limitRange, metav1.CreateOptions{})
+		if err != nil {
+			logger.Error(errors.New("创建LimitRange失败, " + err.Error()))
+			return errors.New("创建LimitRange失败, " + err.Error())
+		}
+	}
+	return nil
+}
+
+// DeleteNamespace 删除namespace
+// confirm为false时只返回namespace中将被删除的资源清单，不做删除；为true时删除并返回清单
+func (n *namespace) DeleteNamespace(client *kubernetes.Clientset, name string, confirm bool) (*NamespaceDeletePreview, error) {
+	if protectedNamespaces[name] {
+		return nil, errors.New("系统namespace不允许删除: " + name)
+	}
+	preview, err := n.previewDelete(client, name)
+	if err != nil {
+		return nil, err
+	}
+	if !confirm {
+		return preview, nil
+	}
+	err = client.CoreV1().Namespaces().Delete(context.TODO(), name, metav1.DeleteOptions{})
+	if err != nil {
+		logger.Error(errors.New("删除Namespace失败, " + err.Error()))
+		return nil, errors.New("删除Namespace失败, " + err.Error())
+	}
+	preview.Deleted = true
+	return preview, nil
+}
+
+// previewDelete 列出namespace中的主要资源
+func (n *namespace) previewDelete(client *kubernetes.Clientset, name string) (*NamespaceDeletePreview, error) {
+	ctx := context.TODO()
+	opts := metav1.ListOptions{}
+	preview := &NamespaceDeletePreview{
+		Namespace: name,
+		Resources: make(map[string][]string),
+	}
+	// 每种资源的list方法，通过meta.ExtractList统一取出资源名称
+	listers := []struct {
+		kind string
+		list func() (runtime.Object, error)
+	}{
+		{"Pod", func() (runtime.Object, error) { return client.CoreV1().Pods(name).List(ctx, opts) }},
+		{"Deployment", func() (runtime.Object, error) { return client.AppsV1().Deployments(name).List(ctx, opts) }},
+		{"StatefulSet", func() (runtime.Object, error) { return client.AppsV1().StatefulSets(name).List(ctx, opts) }},
+		{"DaemonSet", func() (runtime.Object, error) { return client.AppsV1().DaemonSets(name).List(ctx, opts) }},
+		{"Service", func() (runtime.Object, error) { return client.CoreV1().Services(name).List(ctx, opts) }},
+		{"Ingress", func() (runtime.Object, error) { return client.NetworkingV1().Ingresses(name).List(ctx, opts) }},
+		{"ConfigMap", func() (runtime.Object, error) { return client.CoreV1().ConfigMaps(name).List(ctx, opts) }},
+		{"Secret", func() (runtime.Object, error) { return client.CoreV1().Secrets(name).List(ctx, opts) }},
+		{"PersistentVolumeClaim", func() (runtime.Object, error) { return client.CoreV1().PersistentVolumeClaims(name).List(ctx, opts) }},
+	}
+	for _, lister := range listers {
+		list, err := lister.list()
+		if err != nil {
+			logger.Error(errors.New("获取" + lister.kind + "列表失败, " + err.Error()))
+			return nil, errors.New("获取" + lister.kind + "列表失败, " + err.Error())
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return nil, errors.New("解析" + lister.kind + "列表失败, " + err.Error())
+		}
+		for _, item := range items {
+			accessor, err := meta.Accessor(item)
+			if err != nil {
+				continue
+			}
+			preview.Resources[lister.kind] = append(preview.Resources[lister.kind], accessor.GetName())
+		}
+	}
+	return preview, nil
+}
+
+// toResourceList 将模板中的字符串资源量转换为ResourceList
+func (n *namespace) toResourceList(data map[string]string) (corev1.ResourceList, error) {
+	list := make(corev1.ResourceList, len(data))
+	for key, value := range data {
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return nil, fmt.Errorf("解析资源%s的值%s失败, %v", key, value, err)
+		}
+		list[corev1.ResourceName(key)] = quantity
+	}
+	return list, nil
+}
+
+func (n *namespace) toCells(std []corev1.Namespace) []DataCell {
+	cells := make([]DataCell, len(std))
+	for i := range std {
+		cells[i] = namespaceCell(std[i])
+	}
+	return cells
+}
+
+func (n *namespace) fromCells(cells []DataCell) []corev1.Namespace {
+	std := make([]corev1.Namespace, len(cells))
+	for i := range cells {
+		std[i] = corev1.Namespace(cells[i].(namespaceCell))
+	}
+	return std
+}
@@ -0,0 +1,431 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/aryming/logger"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  networkpolicy.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-04-27 10:00
+ */
+
+var NetworkPolicy networkPolicy
+
+type networkPolicy struct{}
+
+// NetworkPoliciesResp 定义列表的返回内容，Items是networkpolicy元素列表，Total为networkpolicy元素数量
+type NetworkPoliciesResp struct {
+	Items []networkingv1.NetworkPolicy `json:"items"`
+	Total int                          `json:"total"`
+}
+
+// NetworkPolicyCreate 定义创建networkpolicy需要的参数属性
+// PodSelector为空时选中namespace下所有pod，PolicyTypes为空时由k8s根据规则推断
+type NetworkPolicyCreate struct {
+	Name        string                                  `json:"name"`
+	Namespace   string                                  `json:"namespace"`
+	PodSelector map[string]string                       `json:"pod_selector"`
+	PolicyTypes []string                                `json:"policy_types"`
+	Ingress     []networkingv1.NetworkPolicyIngressRule `json:"ingress"`
+	Egress      []networkingv1.NetworkPolicyEgressRule  `json:"egress"`
+	Cluster     string                                  `json:"cluster"`
+}
+
+// ReachabilityQuery 定义连通性判断的参数，Protocol为空时默认TCP
+type ReachabilityQuery struct {
+	SourceNamespace      string `json:"source_namespace"`
+	SourcePod            string `json:"source_pod"`
+	DestinationNamespace string `json:"destination_namespace"`
+	DestinationPod       string `json:"destination_pod"`
+	Port                 int32  `json:"port"`
+	Protocol             string `json:"protocol"`
+}
+
+// PolicyVerdict 定义单个方向(源pod出站或目标pod入站)的判断结果
+// Isolated为false表示没有policy选中该pod，该方向默认放行
+// MatchedPolicy和MatchedRule为放行流量的policy及规则下标，拒绝时为空和-1
+type PolicyVerdict struct {
+	Isolated      bool     `json:"isolated"`
+	Allowed       bool     `json:"allowed"`
+	Policies      []string `json:"policies"`
+	MatchedPolicy string   `json:"matched_policy"`
+	MatchedRule   int      `json:"matched_rule"`
+	Reason        string   `json:"reason"`
+}
+
+// ReachabilityResult 定义连通性判断的结果，出站和入站都放行时流量才可达
+type ReachabilityResult struct {
+	Allowed bool           `json:"allowed"`
+	Egress  *PolicyVerdict `json:"egress"`
+	Ingress *PolicyVerdict `json:"ingress"`
+}
+
+// GetNetworkPolicies 获取networkpolicy列表，支持过滤、排序、分页
+func (n *networkPolicy) GetNetworkPolicies(client *kubernetes.Clientset, filterName, namespace string, limit, page int) (*NetworkPoliciesResp, error) {
+	networkPolicyList, err := client.NetworkingV1().NetworkPolicies(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取NetworkPolicy列表失败, " + err.Error()))
+		return nil, errors.New("获取NetworkPolicy列表失败, " + err.Error())
+	}
+	selectableData := &dataSelector{
+		GenericDateSelect: n.toCells(networkPolicyList.Items),
+		dataSelectQuery: &DataSelectQuery{
+			FilterQuery: &FilterQuery{Name: filterName},
+			PaginationQuery: &PaginationQuery{
+				Limit: limit,
+				Page:  page,
+			},
+		},
+	}
+	filtered := selectableData.Filter()
+	total := len(filtered.GenericDateSelect)
+	data := filtered.Sort().Paginate()
+
+	networkPolicies := n.fromCells(data.GenericDateSelect)
+	return &NetworkPoliciesResp{
+		Items: networkPolicies,
+		Total: total,
+	}, nil
+}
+
+// GetNetworkPolicyDetail 获取networkpolicy详情
+func (n *networkPolicy) GetNetworkPolicyDetail(client *kubernetes.Clientset, namespace, name string) (*networkingv1.NetworkPolicy, error) {
+	networkPolicy, err := client.NetworkingV1().NetworkPolicies(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取NetworkPolicy详情失败, " + err.Error()))
+		return nil, errors.New("获取NetworkPolicy详情失败, " + err.Error())
+	}
+	return networkPolicy, nil
+}
+
+// CreateNetworkPolicy 创建networkpolicy,接收NetworkPolicyCreate对象
+func (n *networkPolicy) CreateNetworkPolicy(client *kubernetes.Clientset, data *NetworkPolicyCreate) (err error) {
+	policyTypes := make([]networkingv1.PolicyType, 0, len(data.PolicyTypes))
+	for _, policyType := range data.PolicyTypes {
+		policyTypes = append(policyTypes, networkingv1.PolicyType(policyType))
+	}
+	networkPolicy := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      data.Name,
+			Namespace: data.Namespace,
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: data.PodSelector},
+			PolicyTypes: policyTypes,
+			Ingress:     data.Ingress,
+			Egress:      data.Egress,
+		},
+	}
+	_, err = client.NetworkingV1().NetworkPolicies(data.Namespace).Create(context.TODO(), networkPolicy, metav1.CreateOptions{})
+	if err != nil {
+		logger.Error(errors.New("创建NetworkPolicy失败, " + err.Error()))
+		return errors.New("创建NetworkPolicy失败, " + err.Error())
+	}
+	return nil
+}
+
+// UpdateNetworkPolicy 更新networkpolicy，content为networkpolicy的完整json
+func (n *networkPolicy) UpdateNetworkPolicy(client *kubernetes.Clientset, namespace, content string) (err error) {
+	var networkPolicy = &networkingv1.NetworkPolicy{}
+	err = json.Unmarshal([]byte(content), networkPolicy)
+	if err != nil {
+		logger.Error(errors.New("反序列化失败, " + err.Error()))
+		return errors.New("反序列化失败, " + err.Error())
+	}
+	_, err = client.NetworkingV1().NetworkPolicies(namespace).Update(context.TODO(), networkPolicy, metav1.UpdateOptions{})
+	if err != nil {
+		logger.Error(errors.New("更新NetworkPolicy失败, " + err.Error()))
+		return errors.New("更新NetworkPolicy失败, " + err.Error())
+	}
+	return nil
+}
+
+// DeleteNetworkPolicy 删除networkpolicy
+func (n *networkPolicy) DeleteNetworkPolicy(client *kubernetes.Clientset, name, namespace string) (err error) {
+	err = client.NetworkingV1().NetworkPolicies(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+	if err != nil {
+		logger.Error(errors.New("删除NetworkPolicy失败, " + err.Error()))
+		return errors.New("删除NetworkPolicy失败, " + err.Error())
+	}
+	return nil
+}
+
+// EvaluateReachability 判断源pod访问目标pod指定端口的流量是否被networkpolicy放行
+// 分别计算源pod的出站(Egress)和目标pod的入站(Ingress)，并给出放行或拒绝的policy规则
+func (n *networkPolicy) EvaluateReachability(client *kubernetes.Clientset, query *ReachabilityQuery) (*ReachabilityResult, error) {
+	if query.Protocol == "" {
+		query.Protocol = string(corev1.ProtocolTCP)
+	}
+	source, err := client.CoreV1().Pods(query.SourceNamespace).Get(context.TODO(), query.SourcePod, metav1.GetOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取源Pod失败, " + err.Error()))
+		return nil, errors.New("获取源Pod失败, " + err.Error())
+	}
+	destination, err := client.CoreV1().Pods(query.DestinationNamespace).Get(context.TODO(), query.DestinationPod, metav1.GetOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取目标Pod失败, " + err.Error()))
+		return nil, errors.New("获取目标Pod失败, " + err.Error())
+	}
+	namespaceList, err := client.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取Namespace列表失败, " + err.Error()))
+		return nil, errors.New("获取Namespace列表失败, " + err.Error())
+	}
+	namespaceLabels := make(map[string]labels.Set, len(namespaceList.Items))
+	for _, item := range namespaceList.Items {
+		namespaceLabels[item.Name] = item.Labels
+	}
+
+	egressPolicies, err := client.NetworkingV1().NetworkPolicies(source.Namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取NetworkPolicy列表失败, " + err.Error()))
+		return nil, errors.New("获取NetworkPolicy列表失败, " + err.Error())
+	}
+	ingressPolicies, err := client.NetworkingV1().NetworkPolicies(destination.Namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取NetworkPolicy列表失败, " + err.Error()))
+		return nil, errors.New("获取NetworkPolicy列表失败, " + err.Error())
+	}
+
+	port := &trafficPort{port: query.Port, protocol: corev1.Protocol(query.Protocol), pod: destination}
+	result := &ReachabilityResult{
+		Egress:  n.evaluateEgress(egressPolicies.Items, source, destination, namespaceLabels, port),
+		Ingress: n.evaluateIngress(ingressPolicies.Items, source, destination, namespaceLabels, port),
+	}
+	result.Allowed = result.Egress.Allowed && result.Ingress.Allowed
+	return result, nil
+}
+
+// trafficPort 定义被访问的端口，pod用于解析networkpolicy中的命名端口
+type trafficPort struct {
+	port     int32
+	protocol corev1.Protocol
+	pod      *corev1.Pod
+}
+
+// evaluateEgress 判断源pod的出站流量是否放行
+func (n *networkPolicy) evaluateEgress(policies []networkingv1.NetworkPolicy, source, destination *corev1.Pod, namespaceLabels map[string]labels.Set, port *trafficPort) *PolicyVerdict {
+	verdict := &PolicyVerdict{Policies: make([]string, 0), MatchedRule: -1}
+	for i := range policies {
+		policy := &policies[i]
+		if !n.hasPolicyType(policy, networkingv1.PolicyTypeEgress) || !n.selectsPod(policy, source) {
+			continue
+		}
+		verdict.Isolated = true
+		verdict.Policies = append(verdict.Policies, policy.Name)
+		if verdict.Allowed {
+			continue
+		}
+		for index, rule := range policy.Spec.Egress {
+			if n.matchPeers(rule.To, policy.Namespace, destination, namespaceLabels) && n.matchPorts(rule.Ports, port) {
+				verdict.Allowed = true
+				verdict.MatchedPolicy = policy.Name
+				verdict.MatchedRule = index
+				verdict.Reason = fmt.Sprintf("源Pod出站流量被NetworkPolicy %s 的第%d条egress规则放行", policy.Name, index+1)
+				break
+			}
+		}
+	}
+	if !verdict.Isolated {
+		verdict.Allowed = true
+		verdict.Reason = "没有NetworkPolicy限制源Pod的出站流量，默认放行"
+	} else if !verdict.Allowed {
+		verdict.Reason = fmt.Sprintf("源Pod的出站流量受NetworkPolicy %v 限制，没有egress规则匹配目标Pod和端口", verdict.Policies)
+	}
+	return verdict
+}
+
+// evaluateIngress 判断目标pod的入站流量是否放行
+func (n *networkPolicy) evaluateIngress(policies []networkingv1.NetworkPolicy, source, destination *corev1.Pod, namespaceLabels map[string]labels.Set, port *trafficPort) *PolicyVerdict {
+	verdict := &PolicyVerdict{Policies: make([]string, 0), MatchedRule: -1}
+	for i := range policies {
+		policy := &policies[i]
+		if !n.hasPolicyType(policy, networkingv1.PolicyTypeIngress) || !n.selectsPod(policy, destination) {
+			continue
+		}
+		verdict.Isolated = true
+		verdict.Policies = append(verdict.Policies, policy.Name)
+		if verdict.Allowed {
+			continue
+		}
+		for index, rule := range policy.Spec.Ingress {
+			if n.matchPeers(rule.From, policy.Namespace, source, namespaceLabels) && n.matchPorts(rule.Ports, port) {
+				verdict.Allowed = true
+				verdict.MatchedPolicy = policy.Name
+				verdict.MatchedRule = index
+				verdict.Reason = fmt.Sprintf("目标Pod入站流量被NetworkPolicy %s 的第%d条ingress规则放行", policy.Name, index+1)
+				break
+			}
+		}
+	}
+	if !verdict.Isolated {
+		verdict.Allowed = true
+		verdict.Reason = "没有NetworkPolicy限制目标Pod的入站流量，默认放行"
+	} else if !verdict.Allowed {
+		verdict.Reason = fmt.Sprintf("目标Pod的入站流量受NetworkPolicy %v 限制，没有ingress规则匹配源Pod和端口", verdict.Policies)
+	}
+	return verdict
+}
+
+// hasPolicyType 判断policy是否作用于指定方向
+// policyTypes为空时，总是包含Ingress，存在egress规则时包含Egress
+func (n *networkPolicy) hasPolicyType(policy *networkingv1.NetworkPolicy, policyType networkingv1.PolicyType) bool {
+	if len(policy.Spec.PolicyTypes) == 0 {
+		return policyType == networkingv1.PolicyTypeIngress || len(policy.Spec.Egress) > 0
+	}
+	for _, item := range policy.Spec.PolicyTypes {
+		if item == policyType {
+			return true
+		}
+	}
+	return false
+}
+
+// selectsPod 判断policy的podSelector是否选中pod
+func (n *networkPolicy) selectsPod(policy *networkingv1.NetworkPolicy, pod *corev1.Pod) bool {
+	if policy.Namespace != pod.Namespace {
+		return false
+	}
+	selector, err := metav1.LabelSelectorAsSelector(&policy.Spec.PodSelector)
+	if err != nil {
+		return false
+	}
+	return selector.Matches(labels.Set(pod.Labels))
+}
+
+// matchPeers 判断规则的peers是否匹配对端pod，peers为空时匹配所有对端
+func (n *networkPolicy) matchPeers(peers []networkingv1.NetworkPolicyPeer, policyNamespace string, pod *corev1.Pod, namespaceLabels map[string]labels.Set) bool {
+	if len(peers) == 0 {
+		return true
+	}
+	for _, peer := range peers {
+		if n.matchPeer(peer, policyNamespace, pod, namespaceLabels) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPeer 判断单个peer是否匹配对端pod
+// 只有podSelector时匹配policy所在namespace的pod，有namespaceSelector时匹配选中namespace的pod
+func (n *networkPolicy) matchPeer(peer networkingv1.NetworkPolicyPeer, policyNamespace string, pod *corev1.Pod, namespaceLabels map[string]labels.Set) bool {
+	if peer.IPBlock != nil {
+		return n.matchIPBlock(peer.IPBlock, pod.Status.PodIP)
+	}
+	if peer.NamespaceSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(peer.NamespaceSelector)
+		if err != nil || !selector.Matches(namespaceLabels[pod.Namespace]) {
+			return false
+		}
+	} else if pod.Namespace != policyNamespace {
+		return false
+	}
+	if peer.PodSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(peer.PodSelector)
+		if err != nil || !selector.Matches(labels.Set(pod.Labels)) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchIPBlock 判断pod ip是否在ipBlock的cidr中且不在except中
+func (n *networkPolicy) matchIPBlock(ipBlock *networkingv1.IPBlock, podIP string) bool {
+	ip := net.ParseIP(podIP)
+	if ip == nil {
+		return false
+	}
+	_, cidr, err := net.ParseCIDR(ipBlock.CIDR)
+	if err != nil || !cidr.Contains(ip) {
+		return false
+	}
+	for _, except := range ipBlock.Except {
+		if _, exceptCidr, err := net.ParseCIDR(except); err == nil && exceptCidr.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchPorts 判断规则的ports是否匹配访问的端口，ports为空时匹配所有端口
+// 命名端口按目标pod容器中同名端口解析，endPort表示端口范围
+func (n *networkPolicy) matchPorts(ports []networkingv1.NetworkPolicyPort, port *trafficPort) bool {
+	if len(ports) == 0 {
+		return true
+	}
+	for _, item := range ports {
+		protocol := corev1.ProtocolTCP
+		if item.Protocol != nil {
+			protocol = *item.Protocol
+		}
+		if protocol != port.protocol {
+			continue
+		}
+		if item.Port == nil {
+			return true
+		}
+		number := item.Port.IntVal
+		if item.Port.StrVal != "" {
+			number = n.resolveNamedPort(port.pod, item.Port.StrVal, protocol)
+		}
+		if number == 0 {
+			continue
+		}
+		if item.EndPort != nil {
+			if port.port >= number && port.port <= *item.EndPort {
+				return true
+			}
+			continue
+		}
+		if port.port == number {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveNamedPort 在pod的容器端口中查找命名端口，找不到时返回0
+func (n *networkPolicy) resolveNamedPort(pod *corev1.Pod, name string, protocol corev1.Protocol) int32 {
+	for _, container := range pod.Spec.Containers {
+		for _, containerPort := range container.Ports {
+			containerProtocol := containerPort.Protocol
+			if containerProtocol == "" {
+				containerProtocol = corev1.ProtocolTCP
+			}
+			if containerPort.Name == name && containerProtocol == protocol {
+				return containerPort.ContainerPort
+			}
+		}
+	}
+	return 0
+}
+
+func (n *networkPolicy) toCells(std []networkingv1.NetworkPolicy) []DataCell {
+	cells := make([]DataCell, len(std))
+	for i := range std {
+		cells[i] = networkPolicyCell(std[i])
+	}
+	return cells
+}
+
+func (n *networkPolicy) fromCells(cells []DataCell) []networkingv1.NetworkPolicy {
+	std := make([]networkingv1.NetworkPolicy, len(cells))
+	for i := range cells {
+		std[i] = networkingv1.NetworkPolicy(cells[i].(networkPolicyCell))
+	}
+	return std
+}
@@ -0,0 +1,315 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"kubea-go/config"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aryming/logger"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  node.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-04-14 09:40
+ */
+
+var Node node
+
+type node struct{}
+
+// 驱逐结果
+const (
+	EvictStatusEvicted = "evicted"
+	EvictStatusSkipped = "skipped"
+	EvictStatusFailed  = "failed"
+)
+
+// NodeItem 定义node列表中每个node的概要信息
+type NodeItem struct {
+	Name              string                 `json:"name"`
+	Roles             []string               `json:"roles"`
+	Ready             bool                   `json:"ready"`
+	Unschedulable     bool                   `json:"unschedulable"`
+	InternalIP        string                 `json:"internal_ip"`
+	KubeletVersion    string                 `json:"kubelet_version"`
+	Capacity          corev1.ResourceList    `json:"capacity"`
+	Allocatable       corev1.ResourceList    `json:"allocatable"`
+	Conditions        []corev1.NodeCondition `json:"conditions"`
+	Taints            []corev1.Taint         `json:"taints"`
+	CreationTimestamp metav1.Time            `json:"creation_timestamp"`
+}
+
+// NodesResp 定义列表的返回内容，Items是node概要信息列表，Total为node元素数量
+type NodesResp struct {
+	Items []*NodeItem `json:"items"`
+	Total int         `json:"total"`
+}
+
+// NodeDetail 定义node详情的返回内容，Pods为运行在该node上的pod
+type NodeDetail struct {
+	Node *corev1.Node `json:"node"`
+	Pods []corev1.Pod `json:"pods"`
+}
+
+// EvictResult 定义drain时单个pod的驱逐结果
+type EvictResult struct {
+	Namespace string `json:"namespace"`
+	PodName   string `json:"pod_name"`
+	Status    string `json:"status"`
+	Reason    string `json:"reason"`
+}
+
+// GetNodes 获取node列表，支持过滤、排序、分页
+func (n *node) GetNodes(client *kubernetes.Clientset, filterName string, limit, page int) (*NodesResp, error) {
+	nodeList, err := client.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取Node列表失败, " + err.Error()))
+		return nil, errors.New("获取Node列表失败, " + err.Error())
+	}
+	selectableData := &dataSelector{
+		GenericDateSelect: n.toCells(nodeList.Items),
+		dataSelectQuery: &DataSelectQuery{
+			FilterQuery: &FilterQuery{Name: filterName},
+			PaginationQuery: &PaginationQuery{
+				Limit: limit,
+				Page:  page,
+			},
+		},
+	}
+	filtered := selectableData.Filter()
+	total := len(filtered.GenericDateSelect)
+	data := filtered.Sort().Paginate()
+
+	nodes := n.fromCells(data.GenericDateSelect)
+	items := make([]*NodeItem, 0, len(nodes))
+	for i := range nodes {
+		items = append(items, n.toItem(&nodes[i]))
+	}
+	return &NodesResp{
+		Items: items,
+		Total: total,
+	}, nil
+}
+
+// GetNodeDetail 获取node详情，包含运行在该node上的pod
+func (n *node) GetNodeDetail(client *kubernetes.Clientset, name string) (*NodeDetail, error) {
+	node, err := client.CoreV1().Nodes().Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取Node详情失败, " + err.Error()))
+		return nil, errors.New("获取Node详情失败, " + err.Error())
+	}
+	pods, err := n.getNodePods(client, name)
+	if err != nil {
+		return nil, err
+	}
+	return &NodeDetail{Node: node, Pods: pods}, nil
+}
+
+// CordonNode 设置node为不可调度
+func (n *node) CordonNode(client *kubernetes.Clientset, name string) (err error) {
+	return n.setUnschedulable(client, name, true)
+}
+
+// UncordonNode 恢复node为可调度
+func (n *node) UncordonNode(client *kubernetes.Clientset, name string) (err error) {
+	return n.setUnschedulable(client, name, false)
+}
+
+// DrainNode 排空node，等同于kubectl drain
+// 先cordon节点，再通过eviction API逐个驱逐pod，驱逐受PodDisruptionBudget约束
+// 违反PodDisruptionBudget的pod按退避间隔重试，直到驱逐成功或ctx结束，ctx结束时仍未驱逐的pod记为失败
+// DaemonSet管理的pod和静态pod会被跳过，返回每个pod的驱逐结果
+func (n *node) DrainNode(ctx context.Context, client *kubernetes.Clientset, name string, gracePeriodSeconds *int64) ([]*EvictResult, error) {
+	if err := n.CordonNode(client, name); err != nil {
+		return nil, err
+	}
+	pods, err := n.getNodePods(client, name)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*EvictResult, 0, len(pods))
+	// pending记录需要驱逐的pod在pods和results中的下标
+	pending := make([]int, 0, len(pods))
+	for i := range pods {
+		pod := &pods[i]
+		result := &EvictResult{Namespace: pod.Namespace, PodName: pod.Name}
+		results = append(results, result)
+		if reason, skip := n.skipEvict(pod); skip {
+			result.Status = EvictStatusSkipped
+			result.Reason = reason
+			continue
+		}
+		pending = append(pending, i)
+	}
+	retryInterval := time.Second
+	for {
+		retry := pending[:0]
+		for _, index := range pending {
+			if !n.evictPod(ctx, client, &pods[index], gracePeriodSeconds, results[index]) {
+				retry = append(retry, index)
+			}
+		}
+		pending = retry
+		if len(pending) == 0 {
+			break
+		}
+		timer := time.NewTimer(retryInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+		case <-timer.C:
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		retryInterval = min(retryInterval*2, config.DrainEvictRetryMaxInterval*time.Second)
+	}
+	for _, result := range results {
+		if result.Status == EvictStatusFailed {
+			logger.Error(errors.New("驱逐Pod " + result.Namespace + "/" + result.PodName + "失败, " + result.Reason))
+		}
+	}
+	return results, nil
+}
+
+// evictPod 驱逐pod并记录结果，违反PodDisruptionBudget需要重试时返回false
+func (n *node) evictPod(ctx context.Context, client *kubernetes.Clientset, pod *corev1.Pod, gracePeriodSeconds *int64, result *EvictResult) bool {
+	eviction := &policyv1.Eviction{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pod.Name,
+			Namespace: pod.Namespace,
+		},
+		DeleteOptions: &metav1.DeleteOptions{GracePeriodSeconds: gracePeriodSeconds},
+	}
+	err := client.PolicyV1().Evictions(pod.Namespace).Evict(ctx, eviction)
+	switch {
+	case err == nil:
+		result.Status = EvictStatusEvicted
+		result.Reason = ""
+	case k8serrors.IsNotFound(err):
+		result.Status = EvictStatusEvicted
+		result.Reason = "pod已不存在"
+	case k8serrors.IsTooManyRequests(err):
+		// eviction API返回429表示驱逐会违反PodDisruptionBudget
+		result.Status = EvictStatusFailed
+		result.Reason = "违反PodDisruptionBudget, " + err.Error()
+		return false
+	case ctx.Err() != nil:
+		// 超时时保留上一次的失败原因
+		if result.Status == "" {
+			result.Status = EvictStatusFailed
+			result.Reason = err.Error()
+		}
+	default:
+		result.Status = EvictStatusFailed
+		result.Reason = err.Error()
+	}
+	return true
+}
+
+// skipEvict 判断drain时是否跳过该pod，返回跳过的原因
+func (n *node) skipEvict(pod *corev1.Pod) (string, bool) {
+	if _, ok := pod.Annotations[corev1.MirrorPodAnnotationKey]; ok {
+		return "静态pod", true
+	}
+	controllerRef := metav1.GetControllerOf(pod)
+	if controllerRef != nil && controllerRef.Kind == "DaemonSet" {
+		return "DaemonSet管理的pod", true
+	}
+	return "", false
+}
+
+// setUnschedulable 修改node的spec.unschedulable
+func (n *node) setUnschedulable(client *kubernetes.Clientset, name string, unschedulable bool) (err error) {
+	patchData := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"unschedulable": unschedulable,
+		},
+	}
+	patchBytes, err := json.Marshal(patchData)
+	if err != nil {
+		logger.Error(errors.New("序列化patchData失败, " + err.Error()))
+		return errors.New("序列化patchData失败, " + err.Error())
+	}
+	_, err = client.CoreV1().Nodes().Patch(context.TODO(), name, types.StrategicMergePatchType, patchBytes, metav1.PatchOptions{})
+	if err != nil {
+		logger.Error(errors.New("设置Node调度状态失败, " + err.Error()))
+		return errors.New("设置Node调度状态失败, " + err.Error())
+	}
+	return nil
+}
+
+// getNodePods 获取运行在node上的所有pod
+func (n *node) getNodePods(client *kubernetes.Clientset, name string) ([]corev1.Pod, error) {
+	podList, err := client.CoreV1().Pods("").List(context.TODO(), metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", name).String(),
+	})
+	if err != nil {
+		logger.Error(errors.New("获取Node上的Pod列表失败, " + err.Error()))
+		return nil, errors.New("获取Node上的Pod列表失败, " + err.Error())
+	}
+	return podList.Items, nil
+}
+
+// toItem 将node转换为列表中的概要信息
+func (n *node) toItem(node *corev1.Node) *NodeItem {
+	item := &NodeItem{
+		Name:              node.Name,
+		Roles:             make([]string, 0),
+		Unschedulable:     node.Spec.Unschedulable,
+		KubeletVersion:    node.Status.NodeInfo.KubeletVersion,
+		Capacity:          node.Status.Capacity,
+		Allocatable:       node.Status.Allocatable,
+		Conditions:        node.Status.Conditions,
+		Taints:            node.Spec.Taints,
+		CreationTimestamp: node.CreationTimestamp,
+	}
+	// 角色来自node-role.kubernetes.io/<role>标签
+	for key := range node.Labels {
+		if role, found := strings.CutPrefix(key, "node-role.kubernetes.io/"); found && role != "" {
+			item.Roles = append(item.Roles, role)
+		}
+	}
+	sort.Strings(item.Roles)
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			item.Ready = condition.Status == corev1.ConditionTrue
+		}
+	}
+	for _, address := range node.Status.Addresses {
+		if address.Type == corev1.NodeInternalIP {
+			item.InternalIP = address.Address
+		}
+	}
+	return item
+}
+
+func (n *node) toCells(std []corev1.Node) []DataCell {
+	cells := make([]DataCell, len(std))
+	for i := range std {
+		cells[i] = nodeCell(std[i])
+	}
+	return cells
+}
+
+func (n *node) fromCells(cells []DataCell) []corev1.Node {
+	std := make([]corev1.Node, len(cells))
+	for i := range cells {
+		std[i] = corev1.Node(cells[i].(nodeCell))
+	}
+	return std
+}
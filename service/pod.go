@@ -34,6 +34,12 @@ type PodsResp struct {
 	Total int          `json:"total"`
 }
 
+// PodDetail 定义pod详情的返回内容，内嵌pod对象，Events为与该pod关联的事件
+type PodDetail struct {
+	*corev1.Pod
+	Events []corev1.Event `json:"events"`
+}
+
 // GetPods 获取pod列表，支持过滤和分页,排序
 func (p *pod) GetPods(client *kubernetes.Clientset, filterName, namespace string, limit, page int) (*PodsResp, error) {
 	// 获取podList类型的pod列表
@@ -62,14 +68,19 @@ func (p *pod) GetPods(client *kubernetes.Clientset, filterName, namespace string
 	return &PodsResp{Items: pods, Total: total}, nil
 }
 
-// GetPodDetail 获取pod详情
-func (p *pod) GetPodDetail(client *kubernetes.Clientset, namespace, podName string) (*corev1.Pod, error) {
+// GetPodDetail 获取pod详情，同时返回与该pod关联的事件
+func (p *pod) GetPodDetail(client *kubernetes.Clientset, namespace, podName string) (*PodDetail, error) {
 	pod, err := client.CoreV1().Pods(namespace).Get(context.TODO(), podName, metav1.GetOptions{})
 	if err != nil {
 		logger.Error(errors.New("获取Pod详情失败, " + err.Error()))
 		return nil, errors.New("获取Pod详情失败, " + err.Error())
 	}
-	return pod, nil
+	// 事件获取失败不影响详情的返回
+	events, err := Event.GetObjectEvents(client, namespace, "Pod", podName)
+	if err != nil {
+		events = []corev1.Event{}
+	}
+	return &PodDetail{Pod: pod, Events: events}, nil
 }
 
 // DeletePod 删除POD
@@ -143,6 +154,16 @@ func (p *pod) GetPodLog(client *kubernetes.Clientset, namespace, podName, contai
 	return buf.String(), nil
 }
 
+// GetPodsBySelector 根据label selector获取pod列表，供service、workload等资源解析其关联的pod
+func (p *pod) GetPodsBySelector(client *kubernetes.Clientset, namespace, selector string) ([]corev1.Pod, error) {
+	podList, err := client.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		logger.Error(errors.New("根据selector获取Pod列表失败, " + err.Error()))
+		return nil, errors.New("根据selector获取Pod列表失败, " + err.Error())
+	}
+	return podList.Items, nil
+}
+
 // toCells 方法用于将pod类型数组，转换成DataCell类型数组
 func (p *pod) toCells(std []corev1.Pod) []DataCell {
 	cells := make([]DataCell, len(std))
@@ -161,3 +182,13 @@ func (p *pod) fromCells(cells []DataCell) []corev1.Pod {
 	}
 	return pods
 }
+
+// isPodReady 判断pod的Ready condition是否为True
+func isPodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
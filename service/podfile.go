@@ -0,0 +1,309 @@
+package service
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"kubea-go/config"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aryming/logger"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  podfile.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-05-11 10:00
+ */
+
+var PodFile podFile
+
+// podFile 通过exec在容器中执行ls、tar等命令实现文件的浏览、下载和上传，与kubectl cp的实现方式一致
+// 容器中需要有sh、stat、du和tar命令
+type podFile struct{}
+
+// 文件类型
+const (
+	PodFileTypeFile  = "file"
+	PodFileTypeDir   = "dir"
+	PodFileTypeLink  = "link"
+	PodFileTypeOther = "other"
+)
+
+// 下载的打包格式
+const (
+	PodFileFormatTar = "tar"
+	PodFileFormatZip = "zip"
+)
+
+// podFileListScript 列出目录$1下的文件(包括隐藏文件)，每行输出 类型|大小|修改时间|权限|./文件名
+// 目录为空时通配符不会展开，用-e过滤掉未展开的通配符
+const podFileListScript = `cd "$1" || exit 1
+for f in .* *; do
+	case "$f" in .|..) continue ;; esac
+	if [ -e "$f" ] || [ -L "$f" ]; then stat -c '%F|%s|%Y|%A|%n' "./$f"; fi
+done`
+
+// PodFileItem 定义目录中的一个文件
+type PodFileItem struct {
+	Name    string    `json:"name"`
+	Type    string    `json:"type"`
+	Size    int64     `json:"size"`
+	Mode    string    `json:"mode"`
+	ModTime time.Time `json:"mod_time"`
+}
+
+// PodFileListResp 定义目录列表的返回内容，Path为清理后的目录路径
+type PodFileListResp struct {
+	Path  string         `json:"path"`
+	Items []*PodFileItem `json:"items"`
+}
+
+// ListFiles 列出容器中目录下的文件，目录排在前面，同类型按名称排序
+func (f *podFile) ListFiles(ctx context.Context, client *kubernetes.Clientset, restConfig *rest.Config, namespace, podName, containerName, dirPath string) (*PodFileListResp, error) {
+	dirPath, err := f.cleanPath(dirPath)
+	if err != nil {
+		return nil, err
+	}
+	stdout := new(bytes.Buffer)
+	err = f.exec(ctx, client, restConfig, namespace, podName, containerName, []string{"sh", "-c", podFileListScript, "sh", dirPath}, nil, stdout)
+	if err != nil {
+		logger.Error(errors.New("获取容器文件列表失败, " + err.Error()))
+		return nil, errors.New("获取容器文件列表失败, " + err.Error())
+	}
+	items := make([]*PodFileItem, 0)
+	for _, line := range strings.Split(stdout.String(), "\n") {
+		fields := strings.SplitN(line, "|", 5)
+		if len(fields) != 5 {
+			continue
+		}
+		size, _ := strconv.ParseInt(fields[1], 10, 64)
+		modTime, _ := strconv.ParseInt(fields[2], 10, 64)
+		items = append(items, &PodFileItem{
+			Name:    strings.TrimPrefix(fields[4], "./"),
+			Type:    f.fileType(fields[0]),
+			Size:    size,
+			Mode:    fields[3],
+			ModTime: time.Unix(modTime, 0),
+		})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if (items[i].Type == PodFileTypeDir) != (items[j].Type == PodFileTypeDir) {
+			return items[i].Type == PodFileTypeDir
+		}
+		return items[i].Name < items[j].Name
+	})
+	return &PodFileListResp{
+		Path:  dirPath,
+		Items: items,
+	}, nil
+}
+
+// CheckDownloadSize 下载前检查文件或目录的大小，超过config.PodFileDownloadMaxSize时返回错误
+// du统计的是占用的磁盘空间，与实际打包大小有差异，下载过程中同样会限制大小
+func (f *podFile) CheckDownloadSize(ctx context.Context, client *kubernetes.Clientset, restConfig *rest.Config, namespace, podName, containerName, filePath string) error {
+	filePath, err := f.cleanPath(filePath)
+	if err != nil {
+		return err
+	}
+	stdout := new(bytes.Buffer)
+	err = f.exec(ctx, client, restConfig, namespace, podName, containerName, []string{"du", "-sk", filePath}, nil, stdout)
+	if err != nil {
+		logger.Error(errors.New("获取容器文件大小失败, " + err.Error()))
+		return errors.New("获取容器文件大小失败, " + err.Error())
+	}
+	fields := strings.Fields(stdout.String())
+	if len(fields) == 0 {
+		return errors.New("获取容器文件大小失败, du没有输出")
+	}
+	sizeKB, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return errors.New("获取容器文件大小失败, " + err.Error())
+	}
+	if sizeKB*1024 > config.PodFileDownloadMaxSize {
+		return errors.New("文件大小超过下载上限" + strconv.Itoa(config.PodFileDownloadMaxSize>>20) + "MB")
+	}
+	return nil
+}
+
+// DownloadFile 将容器中的文件或目录打包写入w，format为tar(默认)或zip，包内路径以文件或目录名开头
+func (f *podFile) DownloadFile(ctx context.Context, client *kubernetes.Clientset, restConfig *rest.Config, namespace, podName, containerName, filePath, format string, w io.Writer) error {
+	filePath, err := f.cleanPath(filePath)
+	if err != nil {
+		return err
+	}
+	// 文件名可能以"-"开头，用"--"结束选项，避免被tar解析为参数；下载根目录时打包"."
+	base := path.Base(filePath)
+	if filePath == "/" {
+		base = "."
+	}
+	command := []string{"tar", "cf", "-", "-C", path.Dir(filePath), "--", base}
+	if format != PodFileFormatZip {
+		err = f.exec(ctx, client, restConfig, namespace, podName, containerName, command, nil, &limitWriter{w: w, remaining: config.PodFileDownloadMaxSize})
+	} else {
+		err = f.downloadZip(ctx, client, restConfig, namespace, podName, containerName, command, w)
+	}
+	if err != nil {
+		logger.Error(errors.New("下载容器文件失败, " + err.Error()))
+		return errors.New("下载容器文件失败, " + err.Error())
+	}
+	return nil
+}
+
+// UploadFile 将content上传到容器的dirPath目录下，文件名为fileName，已存在的同名文件会被覆盖
+func (f *podFile) UploadFile(ctx context.Context, client *kubernetes.Clientset, restConfig *rest.Config, namespace, podName, containerName, dirPath, fileName string, size int64, content io.Reader) error {
+	dirPath, err := f.cleanPath(dirPath)
+	if err != nil {
+		return err
+	}
+	if size > config.PodFileUploadMaxSize {
+		return errors.New("文件大小超过上传上限" + strconv.Itoa(config.PodFileUploadMaxSize>>20) + "MB")
+	}
+	fileName = path.Base(fileName)
+	if fileName == "." || fileName == ".." || fileName == "/" {
+		return errors.New("上传的文件名不合法: " + fileName)
+	}
+	// 将文件打包为只包含一个文件的tar流，作为容器中tar命令的stdin
+	reader, writer := io.Pipe()
+	go func() {
+		tarWriter := tar.NewWriter(writer)
+		err := tarWriter.WriteHeader(&tar.Header{
+			Name:     fileName,
+			Mode:     0644,
+			Size:     size,
+			ModTime:  time.Now(),
+			Typeflag: tar.TypeReg,
+		})
+		if err == nil {
+			_, err = io.CopyN(tarWriter, content, size)
+		}
+		if err == nil {
+			err = tarWriter.Close()
+		}
+		_ = writer.CloseWithError(err)
+	}()
+	// exec提前结束时关闭reader，避免打包协程阻塞
+	defer reader.Close()
+	err = f.exec(ctx, client, restConfig, namespace, podName, containerName, []string{"tar", "xmf", "-", "-C", dirPath}, reader, io.Discard)
+	if err != nil {
+		logger.Error(errors.New("上传容器文件失败, " + err.Error()))
+		return errors.New("上传容器文件失败, " + err.Error())
+	}
+	return nil
+}
+
+// downloadZip 执行tar命令并将输出的tar流转换为zip写入w，只保留普通文件和目录
+func (f *podFile) downloadZip(ctx context.Context, client *kubernetes.Clientset, restConfig *rest.Config, namespace, podName, containerName string, command []string, w io.Writer) error {
+	reader, writer := io.Pipe()
+	defer reader.Close()
+	go func() {
+		err := f.exec(ctx, client, restConfig, namespace, podName, containerName, command, nil, &limitWriter{w: writer, remaining: config.PodFileDownloadMaxSize})
+		_ = writer.CloseWithError(err)
+	}()
+	tarReader := tar.NewReader(reader)
+	zipWriter := zip.NewWriter(w)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg && header.Typeflag != tar.TypeDir {
+			continue
+		}
+		zipHeader, err := zip.FileInfoHeader(header.FileInfo())
+		if err != nil {
+			return err
+		}
+		zipHeader.Name = header.Name
+		if header.Typeflag == tar.TypeDir {
+			zipHeader.Name = strings.TrimSuffix(header.Name, "/") + "/"
+		} else {
+			zipHeader.Method = zip.Deflate
+		}
+		entry, err := zipWriter.CreateHeader(zipHeader)
+		if err != nil {
+			return err
+		}
+		if header.Typeflag == tar.TypeReg {
+			if _, err := io.Copy(entry, tarReader); err != nil {
+				return err
+			}
+		}
+	}
+	return zipWriter.Close()
+}
+
+// exec 在容器中执行命令，命令失败时返回stderr的内容
+func (f *podFile) exec(ctx context.Context, client *kubernetes.Clientset, restConfig *rest.Config, namespace, podName, containerName string, command []string, stdin io.Reader, stdout io.Writer) error {
+	executor, err := newExecutor(client, restConfig, namespace, podName, &corev1.PodExecOptions{
+		Container: containerName,
+		Command:   command,
+		Stdin:     stdin != nil,
+		Stdout:    true,
+		Stderr:    true,
+	})
+	if err != nil {
+		return err
+	}
+	stderr := new(bytes.Buffer)
+	err = executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdin:  stdin,
+		Stdout: stdout,
+		Stderr: stderr,
+	})
+	if err != nil && stderr.Len() > 0 {
+		return errors.New(err.Error() + ", " + strings.TrimSpace(stderr.String()))
+	}
+	return err
+}
+
+// cleanPath 清理路径，只允许绝对路径
+func (f *podFile) cleanPath(filePath string) (string, error) {
+	if !path.IsAbs(filePath) {
+		return "", errors.New("路径必须为绝对路径: " + filePath)
+	}
+	return path.Clean(filePath), nil
+}
+
+// fileType 将stat输出的文件类型转换为PodFileType
+func (f *podFile) fileType(statType string) string {
+	switch statType {
+	case "regular file", "regular empty file":
+		return PodFileTypeFile
+	case "directory":
+		return PodFileTypeDir
+	case "symbolic link":
+		return PodFileTypeLink
+	}
+	return PodFileTypeOther
+}
+
+// limitWriter 限制写入的总大小，超过时返回错误
+type limitWriter struct {
+	w         io.Writer
+	remaining int64
+}
+
+func (l *limitWriter) Write(p []byte) (int, error) {
+	if int64(len(p)) > l.remaining {
+		return 0, errors.New("文件大小超过下载上限" + strconv.Itoa(config.PodFileDownloadMaxSize>>20) + "MB")
+	}
+	l.remaining -= int64(len(p))
+	return l.w.Write(p)
+}
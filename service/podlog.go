@@ -0,0 +1,309 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"kubea-go/config"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aryming/logger"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  podlog.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-05-09 10:00
+ */
+
+// PodLogQuery 定义获取容器日志的参数
+// TailLines为空时使用config.PodLogTailLine，小于0时获取全部日志
+// SinceSeconds和SinceTime(RFC3339格式)只能设置一个，UntilTime(RFC3339格式)为日志的截止时间
+// Previous为true时获取上一次退出的容器的日志
+type PodLogQuery struct {
+	Container    string `form:"container_name"`
+	TailLines    *int64 `form:"tail_lines"`
+	SinceSeconds int64  `form:"since_seconds"`
+	SinceTime    string `form:"since_time"`
+	UntilTime    string `form:"until_time"`
+	Timestamps   bool   `form:"timestamps"`
+	Previous     bool   `form:"previous"`
+}
+
+// PodLogLine 定义聚合日志中的一行，Pod和Container为日志的来源
+type PodLogLine struct {
+	Pod       string `json:"pod"`
+	Container string `json:"container"`
+	Content   string `json:"content"`
+}
+
+// String 返回以pod/container为前缀的日志行
+func (l *PodLogLine) String() string {
+	return "[" + l.Pod + "/" + l.Container + "] " + l.Content
+}
+
+// toPodLogOptions 将PodLogQuery转换为corev1.PodLogOptions
+func (q *PodLogQuery) toPodLogOptions(follow bool) (*corev1.PodLogOptions, error) {
+	options := &corev1.PodLogOptions{
+		Container:  q.Container,
+		Follow:     follow,
+		Timestamps: q.Timestamps,
+		Previous:   q.Previous,
+	}
+	tailLines := int64(config.PodLogTailLine)
+	if q.TailLines != nil {
+		tailLines = *q.TailLines
+	}
+	if tailLines >= 0 {
+		options.TailLines = &tailLines
+	}
+	if q.SinceSeconds > 0 && q.SinceTime != "" {
+		return nil, errors.New("since_seconds和since_time只能设置一个")
+	}
+	if q.SinceSeconds > 0 {
+		options.SinceSeconds = &q.SinceSeconds
+	}
+	if q.SinceTime != "" {
+		sinceTime, err := time.Parse(time.RFC3339, q.SinceTime)
+		if err != nil {
+			return nil, errors.New("解析since_time失败, " + err.Error())
+		}
+		options.SinceTime = &metav1.Time{Time: sinceTime}
+	}
+	return options, nil
+}
+
+// FollowPodLog 持续获取容器日志，每读到一行调用一次onLine，行内容不包含换行符
+// ctx被取消(例如客户端断开连接)、容器退出或onLine返回错误时结束，ctx被取消时不返回错误
+func (p *pod) FollowPodLog(ctx context.Context, client *kubernetes.Clientset, namespace, podName string, query *PodLogQuery, onLine func(line string) error) error {
+	return p.readPodLog(ctx, client, namespace, podName, query, true, onLine)
+}
+
+// ReadPodLog 读取容器当前的日志，每读到一行调用一次onLine，TailLines为空时读取全部日志
+func (p *pod) ReadPodLog(ctx context.Context, client *kubernetes.Clientset, namespace, podName string, query *PodLogQuery, onLine func(line string) error) error {
+	allQuery := *query
+	if allQuery.TailLines == nil {
+		allLines := int64(-1)
+		allQuery.TailLines = &allLines
+	}
+	return p.readPodLog(ctx, client, namespace, podName, &allQuery, false, onLine)
+}
+
+// readPodLog 读取容器日志并逐行调用onLine
+// 设置了UntilTime时向apiserver请求带时间戳的日志，读到晚于UntilTime的行时结束，未要求时间戳时去掉时间戳再调用onLine
+func (p *pod) readPodLog(ctx context.Context, client *kubernetes.Clientset, namespace, podName string, query *PodLogQuery, follow bool, onLine func(line string) error) error {
+	options, err := query.toPodLogOptions(follow)
+	if err != nil {
+		return err
+	}
+	var untilTime time.Time
+	if query.UntilTime != "" {
+		if untilTime, err = time.Parse(time.RFC3339, query.UntilTime); err != nil {
+			return errors.New("解析until_time失败, " + err.Error())
+		}
+		options.Timestamps = true
+	}
+	podLogs, err := client.CoreV1().Pods(namespace).GetLogs(podName, options).Stream(ctx)
+	if err != nil {
+		logger.Error(errors.New("获取Pod日志失败, " + err.Error()))
+		return errors.New("获取Pod日志失败, " + err.Error())
+	}
+	defer podLogs.Close()
+	// 不使用bufio.Scanner，避免超长的日志行超过缓冲区大小导致读取失败
+	reader := bufio.NewReader(podLogs)
+	for {
+		line, err := reader.ReadString('\n')
+		if len(line) > 0 {
+			line = strings.TrimRight(line, "\r\n")
+			if !untilTime.IsZero() {
+				// 带时间戳的日志行格式为: 2006-01-02T15:04:05.999999999Z 内容
+				timestamp, content, _ := strings.Cut(line, " ")
+				if logTime, err := time.Parse(time.RFC3339Nano, timestamp); err == nil && logTime.After(untilTime) {
+					return nil
+				}
+				if !query.Timestamps {
+					line = content
+				}
+			}
+			if err := onLine(line); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF || ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			logger.Error(errors.New("读取Pod日志失败, " + err.Error()))
+			return errors.New("读取Pod日志失败, " + err.Error())
+		}
+	}
+}
+
+// FollowSelectorLog 持续获取label selector匹配的所有pod中容器的日志并合并，每读到一行调用一次onLine
+// query.Container不为空时只获取该容器的日志，发布过程中新创建的pod和重启的容器在运行后从头开始获取
+// ctx被取消或onLine返回错误时结束，ctx被取消时不返回错误
+func (p *pod) FollowSelectorLog(ctx context.Context, client *kubernetes.Clientset, namespace, selector string, query *PodLogQuery, onLine func(line *PodLogLine) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	aggregator := &podLogAggregator{
+		client:    client,
+		namespace: namespace,
+		query:     query,
+		onLine:    onLine,
+		cancel:    cancel,
+		followed:  make(map[string]bool),
+	}
+	// 返回前结束所有日志读取协程，避免协程在返回后继续调用onLine
+	defer func() {
+		cancel()
+		aggregator.wg.Wait()
+	}()
+	initial := true
+	for {
+		podList, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			if ctx.Err() != nil {
+				return aggregator.getErr()
+			}
+			logger.Error(errors.New("获取Pod列表失败, " + err.Error()))
+			return errors.New("获取Pod列表失败, " + err.Error())
+		}
+		// 重新监听期间可能遗漏pod的删除事件，清理不在列表中的pod
+		podNames := make(map[string]bool, len(podList.Items))
+		for i := range podList.Items {
+			podNames[podList.Items[i].Name] = true
+		}
+		aggregator.prune(func(podName string) bool {
+			return !podNames[podName]
+		})
+		for i := range podList.Items {
+			aggregator.follow(ctx, &podList.Items[i], initial)
+		}
+		initial = false
+
+		watcher, err := client.CoreV1().Pods(namespace).Watch(ctx, metav1.ListOptions{
+			LabelSelector:   selector,
+			ResourceVersion: podList.ResourceVersion,
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return aggregator.getErr()
+			}
+			logger.Error(errors.New("监听Pod失败, " + err.Error()))
+			return errors.New("监听Pod失败, " + err.Error())
+		}
+		for event := range watcher.ResultChan() {
+			pod, ok := event.Object.(*corev1.Pod)
+			if !ok {
+				continue
+			}
+			// 删除的pod不再读取，statefulset等重建同名pod时可以重新开始读取
+			if event.Type == watch.Deleted {
+				aggregator.prune(func(podName string) bool {
+					return podName == pod.Name
+				})
+				continue
+			}
+			aggregator.follow(ctx, pod, false)
+		}
+		watcher.Stop()
+		// watch被apiserver关闭时重新获取pod列表并继续监听
+		if ctx.Err() != nil {
+			return aggregator.getErr()
+		}
+	}
+}
+
+// podLogAggregator 为每个运行中的容器启动一个日志读取协程，并串行调用onLine
+type podLogAggregator struct {
+	client    *kubernetes.Clientset
+	namespace string
+	query     *PodLogQuery
+	onLine    func(line *PodLogLine) error
+	cancel    context.CancelFunc
+	// followed记录已经开始读取的容器，key为pod/container/restartCount，只在监听协程中访问
+	followed map[string]bool
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+	err      error
+}
+
+// follow 为pod中已经运行的容器(包括init容器)启动日志读取，已经在读取的容器不重复启动
+// initial为false表示容器是监听到的新容器，从头获取日志，避免遗漏启动阶段的输出
+func (a *podLogAggregator) follow(ctx context.Context, pod *corev1.Pod, initial bool) {
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		if a.query.Container != "" && status.Name != a.query.Container {
+			continue
+		}
+		if status.State.Running == nil && status.State.Terminated == nil {
+			continue
+		}
+		key := pod.Name + "/" + status.Name + "/" + strconv.Itoa(int(status.RestartCount))
+		if a.followed[key] {
+			continue
+		}
+		a.followed[key] = true
+
+		query := *a.query
+		query.Container = status.Name
+		if !initial {
+			allLines := int64(-1)
+			query.TailLines = &allLines
+			query.Previous = false
+		}
+		podName := pod.Name
+		a.wg.Add(1)
+		go func() {
+			defer a.wg.Done()
+			err := Pod.FollowPodLog(ctx, a.client, a.namespace, podName, &query, func(line string) error {
+				return a.emit(&PodLogLine{Pod: podName, Container: query.Container, Content: line})
+			})
+			// 单个容器读取失败不影响其他容器，将错误作为该容器的一行日志输出
+			if err != nil && ctx.Err() == nil {
+				_ = a.emit(&PodLogLine{Pod: podName, Container: query.Container, Content: err.Error()})
+			}
+		}()
+	}
+}
+
+// prune 删除shouldPrune返回true的pod的读取记录，读取协程在日志结束时自行退出
+func (a *podLogAggregator) prune(shouldPrune func(podName string) bool) {
+	for key := range a.followed {
+		podName, _, _ := strings.Cut(key, "/")
+		if shouldPrune(podName) {
+			delete(a.followed, key)
+		}
+	}
+}
+
+// emit 串行调用onLine，onLine返回错误时结束所有日志读取
+func (a *podLogAggregator) emit(line *PodLogLine) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.err != nil {
+		return a.err
+	}
+	if err := a.onLine(line); err != nil {
+		a.err = err
+		a.cancel()
+		return err
+	}
+	return nil
+}
+
+// getErr 获取onLine返回的错误
+func (a *podLogAggregator) getErr() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.err
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"kubea-go/config"
+	"regexp"
+	"strings"
+
+	"k8s.io/client-go/kubernetes"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  podlogsearch.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-05-10 10:00
+ */
+
+// PodLogSearch 定义日志搜索的条件，Regex为true时Keyword为正则表达式，否则按子串匹配
+// Context为匹配行前后各返回的行数，MaxMatches为最多返回的匹配行数，为0时使用config.PodLogSearchMaxMatches
+type PodLogSearch struct {
+	Keyword    string `form:"keyword"`
+	Regex      bool   `form:"regex"`
+	IgnoreCase bool   `form:"ignore_case"`
+	Context    int    `form:"context"`
+	MaxMatches int    `form:"max_matches"`
+}
+
+// PodLogSearchLine 定义搜索结果中的一行，Number为在读取到的日志中的行号(从1开始)，Match为false表示上下文行
+type PodLogSearchLine struct {
+	Number  int    `json:"number"`
+	Content string `json:"content"`
+	Match   bool   `json:"match"`
+}
+
+// PodLogSearchBlock 定义一段连续的搜索结果，上下文重叠的匹配行合并为一段
+type PodLogSearchBlock struct {
+	Lines []*PodLogSearchLine `json:"lines"`
+}
+
+// PodLogSearchResp 定义日志搜索的返回内容，Total为返回的匹配行数，Truncated为true表示匹配行超过上限，后面的日志未搜索
+type PodLogSearchResp struct {
+	Blocks    []*PodLogSearchBlock `json:"blocks"`
+	Total     int                  `json:"total"`
+	Truncated bool                 `json:"truncated"`
+}
+
+// SearchPodLog 在服务端搜索容器日志，返回匹配行及其上下文，TailLines为空时搜索全部日志
+func (p *pod) SearchPodLog(ctx context.Context, client *kubernetes.Clientset, namespace, podName string, query *PodLogQuery, search *PodLogSearch) (*PodLogSearchResp, error) {
+	match, err := search.matcher()
+	if err != nil {
+		return nil, err
+	}
+	contextLines := search.Context
+	if contextLines < 0 {
+		contextLines = 0
+	}
+	if contextLines > config.PodLogSearchMaxContext {
+		contextLines = config.PodLogSearchMaxContext
+	}
+	maxMatches := search.MaxMatches
+	if maxMatches <= 0 || maxMatches > config.PodLogSearchMaxMatches {
+		maxMatches = config.PodLogSearchMaxMatches
+	}
+
+	var (
+		resp = &PodLogSearchResp{Blocks: make([]*PodLogSearchBlock, 0)}
+		// current为当前正在追加的结果段，before为尚未加入结果的最近contextLines行
+		current    *PodLogSearchBlock
+		before     = make([]*PodLogSearchLine, 0, contextLines)
+		afterLeft  int
+		number     int
+		errStopped = errors.New("匹配行超过上限")
+	)
+	err = p.ReadPodLog(ctx, client, namespace, podName, query, func(content string) error {
+		number++
+		line := &PodLogSearchLine{Number: number, Content: content}
+		if !match(content) {
+			if current != nil && afterLeft > 0 {
+				current.Lines = append(current.Lines, line)
+				afterLeft--
+				return nil
+			}
+			if contextLines > 0 {
+				if len(before) == contextLines {
+					before = before[1:]
+				}
+				before = append(before, line)
+			}
+			return nil
+		}
+		if resp.Total == maxMatches {
+			resp.Truncated = true
+			return errStopped
+		}
+		resp.Total++
+		line.Match = true
+		// before中的行与当前段的最后一行连续时合并到当前段，否则开始新的一段
+		if current == nil || current.Lines[len(current.Lines)-1].Number+len(before) != number-1 {
+			current = &PodLogSearchBlock{Lines: make([]*PodLogSearchLine, 0, len(before)+1)}
+			resp.Blocks = append(resp.Blocks, current)
+		}
+		current.Lines = append(current.Lines, before...)
+		current.Lines = append(current.Lines, line)
+		before = before[:0]
+		afterLeft = contextLines
+		return nil
+	})
+	if err != nil && !errors.Is(err, errStopped) {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// matcher 根据搜索条件生成匹配函数
+func (s *PodLogSearch) matcher() (func(line string) bool, error) {
+	if s.Keyword == "" {
+		return nil, errors.New("搜索关键字不能为空")
+	}
+	if s.Regex {
+		expr := s.Keyword
+		if s.IgnoreCase {
+			expr = "(?i)" + expr
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, errors.New("解析正则表达式失败, " + err.Error())
+		}
+		return re.MatchString, nil
+	}
+	if s.IgnoreCase {
+		keyword := strings.ToLower(s.Keyword)
+		return func(line string) bool {
+			return strings.Contains(strings.ToLower(line), keyword)
+		}, nil
+	}
+	return func(line string) bool {
+		return strings.Contains(line, s.Keyword)
+	}, nil
+}
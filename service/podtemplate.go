@@ -0,0 +1,321 @@
+package service
+
+import (
+	"errors"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description: 创建工作负载时pod模板的参数定义及转换
+ * @File:  podtemplate.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-05-07 10:00
+ */
+
+// volume的来源类型
+const (
+	VolumeTypeEmptyDir  = "emptyDir"
+	VolumeTypeConfigMap = "configMap"
+	VolumeTypeSecret    = "secret"
+	VolumeTypePvc       = "pvc"
+	VolumeTypeHostPath  = "hostPath"
+)
+
+// PodSpecCreate 定义创建pod模板需要的参数属性，内嵌在工作负载的创建参数中
+type PodSpecCreate struct {
+	Containers       []*ContainerCreate  `json:"containers"`
+	InitContainers   []*ContainerCreate  `json:"init_containers"`
+	Volumes          []*VolumeCreate     `json:"volumes"`
+	ImagePullSecrets []string            `json:"image_pull_secrets"`
+	NodeSelector     map[string]string   `json:"node_selector"`
+	Tolerations      []corev1.Toleration `json:"tolerations"`
+	Affinity         *corev1.Affinity    `json:"affinity"`
+}
+
+// ContainerCreate 定义创建容器需要的参数属性
+// HealthCheck为true时使用HealthPath和第一个端口生成http类型的readiness和liveness探针
+type ContainerCreate struct {
+	Name         string                 `json:"name"`
+	Image        string                 `json:"image"`
+	Command      []string               `json:"command"`
+	Args         []string               `json:"args"`
+	Ports        []*ContainerPortCreate `json:"ports"`
+	Env          []*EnvCreate           `json:"env"`
+	EnvFrom      []*EnvFromCreate       `json:"env_from"`
+	VolumeMounts []*VolumeMountCreate   `json:"volume_mounts"`
+	Requests     *ResourceCreate        `json:"requests"`
+	Limits       *ResourceCreate        `json:"limits"`
+	HealthCheck  bool                   `json:"health_check"`
+	HealthPath   string                 `json:"health_path"`
+}
+
+// ContainerPortCreate 定义容器端口，Protocol为空时默认TCP
+type ContainerPortCreate struct {
+	Name          string `json:"name"`
+	ContainerPort int32  `json:"container_port"`
+	Protocol      string `json:"protocol"`
+}
+
+// EnvCreate 定义环境变量，Value为字面值
+// ConfigMap或Secret不为空时从对应对象的Key中读取，Optional为true时对象或key不存在也能启动
+type EnvCreate struct {
+	Name      string `json:"name"`
+	Value     string `json:"value"`
+	ConfigMap string `json:"configmap"`
+	Secret    string `json:"secret"`
+	Key       string `json:"key"`
+	Optional  bool   `json:"optional"`
+}
+
+// EnvFromCreate 定义从整个ConfigMap或Secret导入环境变量，Prefix为变量名前缀
+type EnvFromCreate struct {
+	ConfigMap string `json:"configmap"`
+	Secret    string `json:"secret"`
+	Prefix    string `json:"prefix"`
+}
+
+// VolumeMountCreate 定义容器的挂载，Name对应VolumeCreate的Name
+type VolumeMountCreate struct {
+	Name      string `json:"name"`
+	MountPath string `json:"mount_path"`
+	SubPath   string `json:"sub_path"`
+	ReadOnly  bool   `json:"read_only"`
+}
+
+// VolumeCreate 定义pod的volume，Type为emptyDir、configMap、secret、pvc或hostPath
+// Source为ConfigMap、Secret、PVC的名称或宿主机路径，emptyDir不需要Source
+type VolumeCreate struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Source string `json:"source"`
+}
+
+// ResourceCreate 定义容器的cpu和内存，为空的字段不设置
+type ResourceCreate struct {
+	Cpu    string `json:"cpu"`
+	Memory string `json:"memory"`
+}
+
+// buildPodSpec 将PodSpecCreate转换为corev1.PodSpec
+func buildPodSpec(data *PodSpecCreate) (corev1.PodSpec, error) {
+	podSpec := corev1.PodSpec{
+		NodeSelector: data.NodeSelector,
+		Tolerations:  data.Tolerations,
+		Affinity:     data.Affinity,
+	}
+	for _, containerCreate := range data.Containers {
+		container, err := buildContainer(containerCreate)
+		if err != nil {
+			return podSpec, err
+		}
+		podSpec.Containers = append(podSpec.Containers, container)
+	}
+	for _, containerCreate := range data.InitContainers {
+		container, err := buildContainer(containerCreate)
+		if err != nil {
+			return podSpec, err
+		}
+		podSpec.InitContainers = append(podSpec.InitContainers, container)
+	}
+	volumes := make(map[string]bool)
+	for _, volumeCreate := range data.Volumes {
+		volume, err := buildVolume(volumeCreate)
+		if err != nil {
+			return podSpec, err
+		}
+		volumes[volume.Name] = true
+		podSpec.Volumes = append(podSpec.Volumes, volume)
+	}
+	// 挂载引用了不存在的volume时apiserver的报错不直观，提前校验
+	containers := append(append([]corev1.Container{}, podSpec.InitContainers...), podSpec.Containers...)
+	for _, container := range containers {
+		for _, mount := range container.VolumeMounts {
+			if !volumes[mount.Name] {
+				return podSpec, errors.New("容器" + container.Name + "挂载的volume不存在: " + mount.Name)
+			}
+		}
+	}
+	for _, secret := range data.ImagePullSecrets {
+		podSpec.ImagePullSecrets = append(podSpec.ImagePullSecrets, corev1.LocalObjectReference{Name: secret})
+	}
+	return podSpec, nil
+}
+
+// buildContainer 将ContainerCreate转换为corev1.Container
+// 请求中的数组元素可能为null，解引用前逐个校验
+func buildContainer(data *ContainerCreate) (corev1.Container, error) {
+	if data == nil {
+		return corev1.Container{}, errors.New("容器配置不能为空")
+	}
+	container := corev1.Container{
+		Name:    data.Name,
+		Image:   data.Image,
+		Command: data.Command,
+		Args:    data.Args,
+	}
+	if data.Name == "" || data.Image == "" {
+		return container, errors.New("容器名称和镜像不能为空")
+	}
+	for _, port := range data.Ports {
+		if port == nil {
+			return container, errors.New("容器" + data.Name + "的端口配置不能为空")
+		}
+		protocol := corev1.ProtocolTCP
+		if port.Protocol != "" {
+			protocol = corev1.Protocol(port.Protocol)
+		}
+		container.Ports = append(container.Ports, corev1.ContainerPort{
+			Name:          port.Name,
+			ContainerPort: port.ContainerPort,
+			Protocol:      protocol,
+		})
+	}
+	for _, env := range data.Env {
+		if env == nil {
+			return container, errors.New("容器" + data.Name + "的环境变量配置不能为空")
+		}
+		container.Env = append(container.Env, buildEnv(env))
+	}
+	for _, envFrom := range data.EnvFrom {
+		if envFrom == nil {
+			return container, errors.New("容器" + data.Name + "的env_from配置不能为空")
+		}
+		source := corev1.EnvFromSource{Prefix: envFrom.Prefix}
+		switch {
+		case envFrom.ConfigMap != "":
+			source.ConfigMapRef = &corev1.ConfigMapEnvSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: envFrom.ConfigMap},
+			}
+		case envFrom.Secret != "":
+			source.SecretRef = &corev1.SecretEnvSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: envFrom.Secret},
+			}
+		default:
+			return container, errors.New("容器" + data.Name + "的env_from需要指定configmap或secret")
+		}
+		container.EnvFrom = append(container.EnvFrom, source)
+	}
+	for _, mount := range data.VolumeMounts {
+		if mount == nil {
+			return container, errors.New("容器" + data.Name + "的volume挂载配置不能为空")
+		}
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      mount.Name,
+			MountPath: mount.MountPath,
+			SubPath:   mount.SubPath,
+			ReadOnly:  mount.ReadOnly,
+		})
+	}
+	var err error
+	if container.Resources.Requests, err = buildResourceList(data.Requests); err != nil {
+		return container, err
+	}
+	if container.Resources.Limits, err = buildResourceList(data.Limits); err != nil {
+		return container, err
+	}
+	if data.HealthCheck {
+		if len(container.Ports) == 0 {
+			return container, errors.New("容器" + data.Name + "开启健康检查时需要设置端口")
+		}
+		container.ReadinessProbe = buildHttpProbe(data.HealthPath, container.Ports[0].ContainerPort, 5)
+		container.LivenessProbe = buildHttpProbe(data.HealthPath, container.Ports[0].ContainerPort, 15)
+	}
+	return container, nil
+}
+
+// buildEnv 将EnvCreate转换为corev1.EnvVar
+func buildEnv(data *EnvCreate) corev1.EnvVar {
+	env := corev1.EnvVar{Name: data.Name}
+	optional := data.Optional
+	switch {
+	case data.ConfigMap != "":
+		env.ValueFrom = &corev1.EnvVarSource{
+			ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: data.ConfigMap},
+				Key:                  data.Key,
+				Optional:             &optional,
+			},
+		}
+	case data.Secret != "":
+		env.ValueFrom = &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: data.Secret},
+				Key:                  data.Key,
+				Optional:             &optional,
+			},
+		}
+	default:
+		env.Value = data.Value
+	}
+	return env
+}
+
+// buildVolume 将VolumeCreate转换为corev1.Volume
+func buildVolume(data *VolumeCreate) (corev1.Volume, error) {
+	if data == nil {
+		return corev1.Volume{}, errors.New("volume配置不能为空")
+	}
+	volume := corev1.Volume{Name: data.Name}
+	switch data.Type {
+	case VolumeTypeEmptyDir:
+		volume.EmptyDir = &corev1.EmptyDirVolumeSource{}
+	case VolumeTypeConfigMap:
+		volume.ConfigMap = &corev1.ConfigMapVolumeSource{
+			LocalObjectReference: corev1.LocalObjectReference{Name: data.Source},
+		}
+	case VolumeTypeSecret:
+		volume.Secret = &corev1.SecretVolumeSource{SecretName: data.Source}
+	case VolumeTypePvc:
+		volume.PersistentVolumeClaim = &corev1.PersistentVolumeClaimVolumeSource{ClaimName: data.Source}
+	case VolumeTypeHostPath:
+		volume.HostPath = &corev1.HostPathVolumeSource{Path: data.Source}
+	default:
+		return volume, errors.New("不支持的volume类型: " + data.Type)
+	}
+	return volume, nil
+}
+
+// buildResourceList 将ResourceCreate转换为corev1.ResourceList，data为空时返回nil
+func buildResourceList(data *ResourceCreate) (corev1.ResourceList, error) {
+	if data == nil || (data.Cpu == "" && data.Memory == "") {
+		return nil, nil
+	}
+	resources := corev1.ResourceList{}
+	if data.Cpu != "" {
+		cpu, err := resource.ParseQuantity(data.Cpu)
+		if err != nil {
+			return nil, errors.New("解析cpu失败, " + err.Error())
+		}
+		resources[corev1.ResourceCPU] = cpu
+	}
+	if data.Memory != "" {
+		memory, err := resource.ParseQuantity(data.Memory)
+		if err != nil {
+			return nil, errors.New("解析memory失败, " + err.Error())
+		}
+		resources[corev1.ResourceMemory] = memory
+	}
+	return resources, nil
+}
+
+// buildHttpProbe 生成http类型的探针
+func buildHttpProbe(path string, port int32, initialDelaySeconds int32) *corev1.Probe {
+	return &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: path,
+				Port: intstr.FromInt32(port),
+			},
+		},
+		//初始化等待时间
+		InitialDelaySeconds: initialDelaySeconds,
+		//超时时间
+		TimeoutSeconds: 5,
+		//执行间隔
+		PeriodSeconds: 5,
+	}
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aryming/logger"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  pv.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-04-23 15:20
+ */
+
+var Pv pv
+
+type pv struct{}
+
+// PvsResp 定义列表的返回内容，Items是pv元素列表，Total为pv元素数量
+type PvsResp struct {
+	Items []corev1.PersistentVolume `json:"items"`
+	Total int                       `json:"total"`
+}
+
+// GetPvs 获取pv列表，支持过滤、排序、分页
+func (p *pv) GetPvs(client *kubernetes.Clientset, filterName string, limit, page int) (*PvsResp, error) {
+	pvList, err := client.CoreV1().PersistentVolumes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取PV列表失败, " + err.Error()))
+		return nil, errors.New("获取PV列表失败, " + err.Error())
+	}
+	selectableData := &dataSelector{
+		GenericDateSelect: p.toCells(pvList.Items),
+		dataSelectQuery: &DataSelectQuery{
+			FilterQuery: &FilterQuery{Name: filterName},
+			PaginationQuery: &PaginationQuery{
+				Limit: limit,
+				Page:  page,
+			},
+		},
+	}
+	filtered := selectableData.Filter()
+	total := len(filtered.GenericDateSelect)
+	data := filtered.Sort().Paginate()
+
+	pvs := p.fromCells(data.GenericDateSelect)
+	return &PvsResp{
+		Items: pvs,
+		Total: total,
+	}, nil
+}
+
+// GetPvDetail 获取pv详情
+func (p *pv) GetPvDetail(client *kubernetes.Clientset, name string) (*corev1.PersistentVolume, error) {
+	pv, err := client.CoreV1().PersistentVolumes().Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取PV详情失败, " + err.Error()))
+		return nil, errors.New("获取PV详情失败, " + err.Error())
+	}
+	return pv, nil
+}
+
+// DeletePv 删除pv
+func (p *pv) DeletePv(client *kubernetes.Clientset, name string) (err error) {
+	err = client.CoreV1().PersistentVolumes().Delete(context.TODO(), name, metav1.DeleteOptions{})
+	if err != nil {
+		logger.Error(errors.New("删除PV失败, " + err.Error()))
+		return errors.New("删除PV失败, " + err.Error())
+	}
+	return nil
+}
+
+func (p *pv) toCells(std []corev1.PersistentVolume) []DataCell {
+	cells := make([]DataCell, len(std))
+	for i := range std {
+		cells[i] = pvCell(std[i])
+	}
+	return cells
+}
+
+func (p *pv) fromCells(cells []DataCell) []corev1.PersistentVolume {
+	std := make([]corev1.PersistentVolume, len(cells))
+	for i := range cells {
+		std[i] = corev1.PersistentVolume(cells[i].(pvCell))
+	}
+	return std
+}
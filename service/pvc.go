@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/aryming/logger"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  pvc.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-04-23 14:00
+ */
+
+var Pvc pvc
+
+type pvc struct{}
+
+// PvcsResp 定义列表的返回内容，Items是pvc元素列表，Total为pvc元素数量
+type PvcsResp struct {
+	Items []corev1.PersistentVolumeClaim `json:"items"`
+	Total int                            `json:"total"`
+}
+
+// PvcCreate 定义创建pvc需要的参数属性，AccessModes为空时默认ReadWriteOnce
+type PvcCreate struct {
+	Name         string            `json:"name"`
+	Namespace    string            `json:"namespace"`
+	Labels       map[string]string `json:"labels"`
+	StorageClass string            `json:"storage_class"`
+	AccessModes  []string          `json:"access_modes"`
+	Storage      string            `json:"storage"`
+	Cluster      string            `json:"cluster"`
+}
+
+// PvcUsage 定义pvc与其绑定的pv以及挂载它的pod之间的关系
+// Orphaned为true表示没有任何pod挂载该pvc
+type PvcUsage struct {
+	Namespace    string   `json:"namespace"`
+	Name         string   `json:"name"`
+	Phase        string   `json:"phase"`
+	StorageClass string   `json:"storage_class"`
+	Capacity     string   `json:"capacity"`
+	VolumeName   string   `json:"volume_name"`
+	VolumePhase  string   `json:"volume_phase"`
+	Pods         []string `json:"pods"`
+	Orphaned     bool     `json:"orphaned"`
+}
+
+// PvcUsageResp 定义pvc使用情况的返回内容，UnboundVolumes为未绑定到任何pvc的pv(Available或Released)
+type PvcUsageResp struct {
+	Items          []*PvcUsage `json:"items"`
+	UnboundVolumes []string    `json:"unbound_volumes"`
+}
+
+// GetPvcs 获取pvc列表，支持过滤、排序、分页
+func (p *pvc) GetPvcs(client *kubernetes.Clientset, filterName, namespace string, limit, page int) (*PvcsResp, error) {
+	pvcList, err := client.CoreV1().PersistentVolumeClaims(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取PVC列表失败, " + err.Error()))
+		return nil, errors.New("获取PVC列表失败, " + err.Error())
+	}
+	selectableData := &dataSelector{
+		GenericDateSelect: p.toCells(pvcList.Items),
+		dataSelectQuery: &DataSelectQuery{
+			FilterQuery: &FilterQuery{Name: filterName},
+			PaginationQuery: &PaginationQuery{
+				Limit: limit,
+				Page:  page,
+			},
+		},
+	}
+	filtered := selectableData.Filter()
+	total := len(filtered.GenericDateSelect)
+	data := filtered.Sort().Paginate()
+
+	pvcs := p.fromCells(data.GenericDateSelect)
+	return &PvcsResp{
+		Items: pvcs,
+		Total: total,
+	}, nil
+}
+
+// GetPvcDetail 获取pvc详情
+func (p *pvc) GetPvcDetail(client *kubernetes.Clientset, namespace, name string) (*corev1.PersistentVolumeClaim, error) {
+	pvc, err := client.CoreV1().PersistentVolumeClaims(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取PVC详情失败, " + err.Error()))
+		return nil, errors.New("获取PVC详情失败, " + err.Error())
+	}
+	return pvc, nil
+}
+
+// CreatePvc 创建pvc,接收PvcCreate对象
+func (p *pvc) CreatePvc(client *kubernetes.Clientset, data *PvcCreate) (err error) {
+	storage, err := resource.ParseQuantity(data.Storage)
+	if err != nil {
+		return errors.New("解析存储容量失败, " + err.Error())
+	}
+	accessModes := make([]corev1.PersistentVolumeAccessMode, 0, len(data.AccessModes))
+	for _, mode := range data.AccessModes {
+		accessModes = append(accessModes, corev1.PersistentVolumeAccessMode(mode))
+	}
+	if len(accessModes) == 0 {
+		accessModes = append(accessModes, corev1.ReadWriteOnce)
+	}
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      data.Name,
+			Namespace: data.Namespace,
+			Labels:    data.Labels,
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: accessModes,
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: storage,
+				},
+			},
+		},
+	}
+	// StorageClass为空时使用集群默认的StorageClass
+	if data.StorageClass != "" {
+		pvc.Spec.StorageClassName = &data.StorageClass
+	}
+	_, err = client.CoreV1().PersistentVolumeClaims(data.Namespace).Create(context.TODO(), pvc, metav1.CreateOptions{})
+	if err != nil {
+		logger.Error(errors.New("创建PVC失败, " + err.Error()))
+		return errors.New("创建PVC失败, " + err.Error())
+	}
+	return nil
+}
+
+// DeletePvc 删除pvc
+func (p *pvc) DeletePvc(client *kubernetes.Clientset, name, namespace string) (err error) {
+	err = client.CoreV1().PersistentVolumeClaims(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+	if err != nil {
+		logger.Error(errors.New("删除PVC失败, " + err.Error()))
+		return errors.New("删除PVC失败, " + err.Error())
+	}
+	return nil
+}
+
+// GetPvcUsage 获取pvc与pv、pod的关联关系，用于删除前找出没有被使用的存储
+// namespace为空时获取整个集群的pvc
+func (p *pvc) GetPvcUsage(client *kubernetes.Clientset, namespace string) (*PvcUsageResp, error) {
+	pvcList, err := client.CoreV1().PersistentVolumeClaims(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取PVC列表失败, " + err.Error()))
+		return nil, errors.New("获取PVC列表失败, " + err.Error())
+	}
+	pvList, err := client.CoreV1().PersistentVolumes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取PV列表失败, " + err.Error()))
+		return nil, errors.New("获取PV列表失败, " + err.Error())
+	}
+	podList, err := client.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取Pod列表失败, " + err.Error()))
+		return nil, errors.New("获取Pod列表失败, " + err.Error())
+	}
+
+	// 以"namespace/pvc名称"为key，记录挂载该pvc的pod
+	// 通用临时卷(ephemeral)的pvc由controller随pod自动创建，名称为"pod名称-卷名称"
+	podsByClaim := make(map[string][]string)
+	for _, item := range podList.Items {
+		for _, volume := range item.Spec.Volumes {
+			var claimName string
+			switch {
+			case volume.PersistentVolumeClaim != nil:
+				claimName = volume.PersistentVolumeClaim.ClaimName
+			case volume.Ephemeral != nil:
+				claimName = item.Name + "-" + volume.Name
+			default:
+				continue
+			}
+			key := item.Namespace + "/" + claimName
+			podsByClaim[key] = append(podsByClaim[key], item.Name)
+		}
+	}
+	volumes := make(map[string]*corev1.PersistentVolume, len(pvList.Items))
+	resp := &PvcUsageResp{
+		Items:          make([]*PvcUsage, 0, len(pvcList.Items)),
+		UnboundVolumes: make([]string, 0),
+	}
+	for i := range pvList.Items {
+		volume := &pvList.Items[i]
+		volumes[volume.Name] = volume
+		if volume.Status.Phase == corev1.VolumeAvailable || volume.Status.Phase == corev1.VolumeReleased {
+			resp.UnboundVolumes = append(resp.UnboundVolumes, volume.Name)
+		}
+	}
+
+	for _, item := range pvcList.Items {
+		usage := &PvcUsage{
+			Namespace:  item.Namespace,
+			Name:       item.Name,
+			Phase:      string(item.Status.Phase),
+			VolumeName: item.Spec.VolumeName,
+			Pods:       podsByClaim[item.Namespace+"/"+item.Name],
+		}
+		if item.Spec.StorageClassName != nil {
+			usage.StorageClass = *item.Spec.StorageClassName
+		}
+		if capacity, ok := item.Status.Capacity[corev1.ResourceStorage]; ok {
+			usage.Capacity = capacity.String()
+		}
+		if volume, ok := volumes[item.Spec.VolumeName]; ok {
+			usage.VolumePhase = string(volume.Status.Phase)
+		}
+		if usage.Pods == nil {
+			usage.Pods = make([]string, 0)
+		}
+		usage.Orphaned = len(usage.Pods) == 0
+		resp.Items = append(resp.Items, usage)
+	}
+	// 未被使用的pvc排在前面
+	sort.SliceStable(resp.Items, func(i, j int) bool {
+		return resp.Items[i].Orphaned && !resp.Items[j].Orphaned
+	})
+	return resp, nil
+}
+
+func (p *pvc) toCells(std []corev1.PersistentVolumeClaim) []DataCell {
+	cells := make([]DataCell, len(std))
+	for i := range std {
+		cells[i] = pvcCell(std[i])
+	}
+	return cells
+}
+
+func (p *pvc) fromCells(cells []DataCell) []corev1.PersistentVolumeClaim {
+	std := make([]corev1.PersistentVolumeClaim, len(cells))
+	for i := range cells {
+		std[i] = corev1.PersistentVolumeClaim(cells[i].(pvcCell))
+	}
+	return std
+}
@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/aryming/logger"
+	rbacv1 "k8s.io/api/rbac/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  rbac.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-04-25 14:10
+ */
+
+var Rbac rbac
+
+type rbac struct{}
+
+// RbacSubject 定义需要查询权限的主体，Kind为User、Group或ServiceAccount
+// Namespace仅对ServiceAccount有效，为空时与查询的namespace相同
+type RbacSubject struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// EffectiveRule 定义主体生效的一条授权规则，以及授权的来源(binding和role)
+type EffectiveRule struct {
+	rbacv1.PolicyRule
+	BindingKind string `json:"binding_kind"`
+	BindingName string `json:"binding_name"`
+	RoleKind    string `json:"role_kind"`
+	RoleName    string `json:"role_name"`
+}
+
+// ResourceVerbs 定义按资源合并后的权限，APIGroup为空表示core组
+// ResourceNames不为空的规则只对指定对象生效，不参与合并，可在Rules中查看
+type ResourceVerbs struct {
+	APIGroup string   `json:"api_group"`
+	Resource string   `json:"resource"`
+	Verbs    []string `json:"verbs"`
+}
+
+// WhoCanResp 定义主体在namespace中的有效权限
+// MissingRoles为binding引用但不存在的role，这类binding不授予任何权限
+type WhoCanResp struct {
+	Subject      *RbacSubject     `json:"subject"`
+	Namespace    string           `json:"namespace"`
+	Groups       []string         `json:"groups"`
+	Rules        []*EffectiveRule `json:"rules"`
+	Resources    []*ResourceVerbs `json:"resources"`
+	MissingRoles []string         `json:"missing_roles"`
+}
+
+// WhoCan 计算主体在namespace中的有效权限，包括namespace中的RoleBinding和所有ClusterRoleBinding授予的规则
+// ServiceAccount和User会同时匹配其隐含所属的system组，例如system:serviceaccounts:<namespace>
+// namespace为空时只计算ClusterRoleBinding授予的集群范围权限，RoleBinding只在其所在namespace生效
+func (r *rbac) WhoCan(client *kubernetes.Clientset, subject *RbacSubject, namespace string) (*WhoCanResp, error) {
+	switch subject.Kind {
+	case rbacv1.UserKind, rbacv1.GroupKind:
+	case rbacv1.ServiceAccountKind:
+		if subject.Namespace == "" {
+			subject.Namespace = namespace
+		}
+	default:
+		return nil, errors.New("不支持的主体类型: " + subject.Kind)
+	}
+	if subject.Name == "" {
+		return nil, errors.New("主体名称不能为空")
+	}
+
+	roleBindings := make([]rbacv1.RoleBinding, 0)
+	if namespace != "" {
+		roleBindingList, err := client.RbacV1().RoleBindings(namespace).List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			logger.Error(errors.New("获取RoleBinding列表失败, " + err.Error()))
+			return nil, errors.New("获取RoleBinding列表失败, " + err.Error())
+		}
+		roleBindings = roleBindingList.Items
+	}
+	clusterRoleBindingList, err := client.RbacV1().ClusterRoleBindings().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取ClusterRoleBinding列表失败, " + err.Error()))
+		return nil, errors.New("获取ClusterRoleBinding列表失败, " + err.Error())
+	}
+
+	groups := r.getImplicitGroups(subject)
+	resp := &WhoCanResp{
+		Subject:      subject,
+		Namespace:    namespace,
+		Groups:       groups,
+		Rules:        make([]*EffectiveRule, 0),
+		MissingRoles: make([]string, 0),
+	}
+	for _, binding := range roleBindings {
+		if !r.matchSubjects(binding.Subjects, subject, groups) {
+			continue
+		}
+		rules, err := r.getRoleRules(client, namespace, binding.RoleRef)
+		if k8serrors.IsNotFound(err) {
+			resp.MissingRoles = append(resp.MissingRoles, binding.RoleRef.Kind+"/"+binding.RoleRef.Name)
+			continue
+		}
+		if err != nil {
+			logger.Error(errors.New("获取" + binding.RoleRef.Kind + "失败, " + err.Error()))
+			return nil, errors.New("获取" + binding.RoleRef.Kind + "失败, " + err.Error())
+		}
+		resp.Rules = append(resp.Rules, r.toEffectiveRules(rules, "RoleBinding", binding.Name, binding.RoleRef)...)
+	}
+	for _, binding := range clusterRoleBindingList.Items {
+		if !r.matchSubjects(binding.Subjects, subject, groups) {
+			continue
+		}
+		rules, err := r.getRoleRules(client, "", binding.RoleRef)
+		if k8serrors.IsNotFound(err) {
+			resp.MissingRoles = append(resp.MissingRoles, binding.RoleRef.Kind+"/"+binding.RoleRef.Name)
+			continue
+		}
+		if err != nil {
+			logger.Error(errors.New("获取" + binding.RoleRef.Kind + "失败, " + err.Error()))
+			return nil, errors.New("获取" + binding.RoleRef.Kind + "失败, " + err.Error())
+		}
+		resp.Rules = append(resp.Rules, r.toEffectiveRules(rules, "ClusterRoleBinding", binding.Name, binding.RoleRef)...)
+	}
+	resp.Resources = r.mergeRules(resp.Rules)
+	return resp, nil
+}
+
+// getImplicitGroups 获取主体隐含所属的组，k8s认证时会自动为主体加上这些组
+func (r *rbac) getImplicitGroups(subject *RbacSubject) []string {
+	switch subject.Kind {
+	case rbacv1.ServiceAccountKind:
+		return []string{"system:serviceaccounts", "system:serviceaccounts:" + subject.Namespace, "system:authenticated"}
+	case rbacv1.UserKind:
+		return []string{"system:authenticated"}
+	}
+	return []string{}
+}
+
+// matchSubjects 判断binding的subjects中是否包含该主体或其隐含所属的组
+func (r *rbac) matchSubjects(subjects []rbacv1.Subject, subject *RbacSubject, groups []string) bool {
+	for _, item := range subjects {
+		switch {
+		case item.Kind == rbacv1.ServiceAccountKind && subject.Kind == rbacv1.ServiceAccountKind:
+			if item.Name == subject.Name && item.Namespace == subject.Namespace {
+				return true
+			}
+		case item.Kind == rbacv1.UserKind && subject.Kind == rbacv1.UserKind:
+			if item.Name == subject.Name {
+				return true
+			}
+		case item.Kind == rbacv1.GroupKind:
+			if subject.Kind == rbacv1.GroupKind && item.Name == subject.Name {
+				return true
+			}
+			for _, group := range groups {
+				if item.Name == group {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// getRoleRules 获取binding引用的role的规则，RoleBinding可以引用Role或ClusterRole
+// role不存在时返回NotFound错误，由调用方记录为MissingRoles，其他错误(例如没有权限)需要返回给调用方
+// 聚合ClusterRole的规则已由控制器写入rules字段，无需单独处理
+func (r *rbac) getRoleRules(client *kubernetes.Clientset, namespace string, roleRef rbacv1.RoleRef) ([]rbacv1.PolicyRule, error) {
+	if roleRef.Kind == "Role" {
+		role, err := client.RbacV1().Roles(namespace).Get(context.TODO(), roleRef.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return role.Rules, nil
+	}
+	clusterRole, err := client.RbacV1().ClusterRoles().Get(context.TODO(), roleRef.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return clusterRole.Rules, nil
+}
+
+func (r *rbac) toEffectiveRules(rules []rbacv1.PolicyRule, bindingKind, bindingName string, roleRef rbacv1.RoleRef) []*EffectiveRule {
+	effectiveRules := make([]*EffectiveRule, 0, len(rules))
+	for _, rule := range rules {
+		effectiveRules = append(effectiveRules, &EffectiveRule{
+			PolicyRule:  rule,
+			BindingKind: bindingKind,
+			BindingName: bindingName,
+			RoleKind:    roleRef.Kind,
+			RoleName:    roleRef.Name,
+		})
+	}
+	return effectiveRules
+}
+
+// mergeRules 将规则按apiGroup和resource合并verbs，verbs包含*时只保留*
+func (r *rbac) mergeRules(rules []*EffectiveRule) []*ResourceVerbs {
+	verbsByResource := make(map[string]map[string]struct{})
+	for _, rule := range rules {
+		if len(rule.ResourceNames) > 0 {
+			continue
+		}
+		for _, group := range rule.APIGroups {
+			for _, resource := range rule.Resources {
+				key := group + "/" + resource
+				if verbsByResource[key] == nil {
+					verbsByResource[key] = make(map[string]struct{})
+				}
+				for _, verb := range rule.Verbs {
+					verbsByResource[key][verb] = struct{}{}
+				}
+			}
+		}
+	}
+	resources := make([]*ResourceVerbs, 0, len(verbsByResource))
+	for key, verbSet := range verbsByResource {
+		group, resource, _ := strings.Cut(key, "/")
+		verbs := make([]string, 0, len(verbSet))
+		if _, ok := verbSet[rbacv1.VerbAll]; ok {
+			verbs = append(verbs, rbacv1.VerbAll)
+		} else {
+			for verb := range verbSet {
+				verbs = append(verbs, verb)
+			}
+			sort.Strings(verbs)
+		}
+		resources = append(resources, &ResourceVerbs{
+			APIGroup: group,
+			Resource: resource,
+			Verbs:    verbs,
+		})
+	}
+	sort.Slice(resources, func(i, j int) bool {
+		if resources[i].APIGroup != resources[j].APIGroup {
+			return resources[i].APIGroup < resources[j].APIGroup
+		}
+		return resources[i].Resource < resources[j].Resource
+	})
+	return resources
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aryming/logger"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  replicaset.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-04-30 10:00
+ */
+
+var ReplicaSet replicaSet
+
+type replicaSet struct{}
+
+// ReplicaSetsResp 定义列表的返回内容，Items是replicaset元素列表，Total为replicaset元素数量
+type ReplicaSetsResp struct {
+	Items []appsv1.ReplicaSet `json:"items"`
+	Total int                 `json:"total"`
+}
+
+// GetReplicaSets 获取replicaset列表，支持过滤、排序、分页
+func (r *replicaSet) GetReplicaSets(client *kubernetes.Clientset, filterName, namespace string, limit, page int) (*ReplicaSetsResp, error) {
+	replicaSetList, err := client.AppsV1().ReplicaSets(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取ReplicaSet列表失败, " + err.Error()))
+		return nil, errors.New("获取ReplicaSet列表失败, " + err.Error())
+	}
+	selectableData := &dataSelector{
+		GenericDateSelect: r.toCells(replicaSetList.Items),
+		dataSelectQuery: &DataSelectQuery{
+			FilterQuery: &FilterQuery{Name: filterName},
+			PaginationQuery: &PaginationQuery{
+				Limit: limit,
+				Page:  page,
+			},
+		},
+	}
+	filtered := selectableData.Filter()
+	total := len(filtered.GenericDateSelect)
+	data := filtered.Sort().Paginate()
+
+	items := r.fromCells(data.GenericDateSelect)
+	return &ReplicaSetsResp{
+		Items: items,
+		Total: total,
+	}, nil
+}
+
+// GetReplicaSetDetail 获取replicaset详情
+func (r *replicaSet) GetReplicaSetDetail(client *kubernetes.Clientset, namespace, name string) (*appsv1.ReplicaSet, error) {
+	replicaSet, err := client.AppsV1().ReplicaSets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取ReplicaSet详情失败, " + err.Error()))
+		return nil, errors.New("获取ReplicaSet详情失败, " + err.Error())
+	}
+	return replicaSet, nil
+}
+
+func (r *replicaSet) toCells(std []appsv1.ReplicaSet) []DataCell {
+	cells := make([]DataCell, len(std))
+	for i := range std {
+		cells[i] = replicaSetCell(std[i])
+	}
+	return cells
+}
+
+func (r *replicaSet) fromCells(cells []DataCell) []appsv1.ReplicaSet {
+	std := make([]appsv1.ReplicaSet, len(cells))
+	for i := range cells {
+		std[i] = appsv1.ReplicaSet(cells[i].(replicaSetCell))
+	}
+	return std
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aryming/logger"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  role.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-04-25 10:20
+ */
+
+var Role role
+
+type role struct{}
+
+// RolesResp 定义列表的返回内容，Items是role元素列表，Total为role元素数量
+type RolesResp struct {
+	Items []rbacv1.Role `json:"items"`
+	Total int           `json:"total"`
+}
+
+// GetRoles 获取role列表，支持过滤、排序、分页
+func (r *role) GetRoles(client *kubernetes.Clientset, filterName, namespace string, limit, page int) (*RolesResp, error) {
+	roleList, err := client.RbacV1().Roles(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取Role列表失败, " + err.Error()))
+		return nil, errors.New("获取Role列表失败, " + err.Error())
+	}
+	selectableData := &dataSelector{
+		GenericDateSelect: r.toCells(roleList.Items),
+		dataSelectQuery: &DataSelectQuery{
+			FilterQuery: &FilterQuery{Name: filterName},
+			PaginationQuery: &PaginationQuery{
+				Limit: limit,
+				Page:  page,
+			},
+		},
+	}
+	filtered := selectableData.Filter()
+	total := len(filtered.GenericDateSelect)
+	data := filtered.Sort().Paginate()
+
+	items := r.fromCells(data.GenericDateSelect)
+	return &RolesResp{
+		Items: items,
+		Total: total,
+	}, nil
+}
+
+// GetRoleDetail 获取role详情
+func (r *role) GetRoleDetail(client *kubernetes.Clientset, namespace, name string) (*rbacv1.Role, error) {
+	role, err := client.RbacV1().Roles(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取Role详情失败, " + err.Error()))
+		return nil, errors.New("获取Role详情失败, " + err.Error())
+	}
+	return role, nil
+}
+
+func (r *role) toCells(std []rbacv1.Role) []DataCell {
+	cells := make([]DataCell, len(std))
+	for i := range std {
+		cells[i] = roleCell(std[i])
+	}
+	return cells
+}
+
+func (r *role) fromCells(cells []DataCell) []rbacv1.Role {
+	std := make([]rbacv1.Role, len(cells))
+	for i := range cells {
+		std[i] = rbacv1.Role(cells[i].(roleCell))
+	}
+	return std
+}
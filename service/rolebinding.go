@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aryming/logger"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  rolebinding.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-04-25 10:50
+ */
+
+var RoleBinding roleBinding
+
+type roleBinding struct{}
+
+// RoleBindingsResp 定义列表的返回内容，Items是rolebinding元素列表，Total为rolebinding元素数量
+type RoleBindingsResp struct {
+	Items []rbacv1.RoleBinding `json:"items"`
+	Total int                  `json:"total"`
+}
+
+// GetRoleBindings 获取rolebinding列表，支持过滤、排序、分页
+func (r *roleBinding) GetRoleBindings(client *kubernetes.Clientset, filterName, namespace string, limit, page int) (*RoleBindingsResp, error) {
+	roleBindingList, err := client.RbacV1().RoleBindings(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取RoleBinding列表失败, " + err.Error()))
+		return nil, errors.New("获取RoleBinding列表失败, " + err.Error())
+	}
+	selectableData := &dataSelector{
+		GenericDateSelect: r.toCells(roleBindingList.Items),
+		dataSelectQuery: &DataSelectQuery{
+			FilterQuery: &FilterQuery{Name: filterName},
+			PaginationQuery: &PaginationQuery{
+				Limit: limit,
+				Page:  page,
+			},
+		},
+	}
+	filtered := selectableData.Filter()
+	total := len(filtered.GenericDateSelect)
+	data := filtered.Sort().Paginate()
+
+	items := r.fromCells(data.GenericDateSelect)
+	return &RoleBindingsResp{
+		Items: items,
+		Total: total,
+	}, nil
+}
+
+// GetRoleBindingDetail 获取rolebinding详情
+func (r *roleBinding) GetRoleBindingDetail(client *kubernetes.Clientset, namespace, name string) (*rbacv1.RoleBinding, error) {
+	roleBinding, err := client.RbacV1().RoleBindings(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取RoleBinding详情失败, " + err.Error()))
+		return nil, errors.New("获取RoleBinding详情失败, " + err.Error())
+	}
+	return roleBinding, nil
+}
+
+func (r *roleBinding) toCells(std []rbacv1.RoleBinding) []DataCell {
+	cells := make([]DataCell, len(std))
+	for i := range std {
+		cells[i] = roleBindingCell(std[i])
+	}
+	return cells
+}
+
+func (r *roleBinding) fromCells(cells []DataCell) []rbacv1.RoleBinding {
+	std := make([]rbacv1.RoleBinding, len(cells))
+	for i := range cells {
+		std[i] = rbacv1.RoleBinding(cells[i].(roleBindingCell))
+	}
+	return std
+}
@@ -0,0 +1,442 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aryming/logger"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  rollout.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-04-30 11:00
+ */
+
+const (
+	// RevisionAnnotation deployment和replicaset上记录版本号的注解
+	RevisionAnnotation = "deployment.kubernetes.io/revision"
+	// ChangeCauseAnnotation 记录变更原因的注解，kubectl rollout history中的CHANGE-CAUSE列
+	ChangeCauseAnnotation = "kubernetes.io/change-cause"
+)
+
+// DeploymentRevision 定义deployment的一个历史版本，对应一个replicaset
+type DeploymentRevision struct {
+	Revision          int64       `json:"revision"`
+	ReplicaSet        string      `json:"replicaset"`
+	Images            []string    `json:"images"`
+	ChangeCause       string      `json:"change_cause"`
+	Replicas          int32       `json:"replicas"`
+	Current           bool        `json:"current"`
+	CreationTimestamp metav1.Time `json:"creation_timestamp"`
+}
+
+// TemplateChange 定义两个版本pod模板中一个字段的差异，From或To为nil表示该字段被新增或删除
+// Path中列表元素有name字段时用name定位，例如spec.containers[name=nginx].image
+type TemplateChange struct {
+	Path string      `json:"path"`
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// RevisionDiff 定义两个版本pod模板的差异
+type RevisionDiff struct {
+	From    int64             `json:"from"`
+	To      int64             `json:"to"`
+	Changes []*TemplateChange `json:"changes"`
+}
+
+// RolloutStatus 定义deployment的发布进度，判断逻辑与kubectl rollout status一致
+// Done为true表示发布完成，DeadlineExceeded为true表示超过progressDeadlineSeconds仍未完成，Paused为true表示发布已暂停
+type RolloutStatus struct {
+	Revision           int64  `json:"revision"`
+	Replicas           int32  `json:"replicas"`
+	UpdatedReplicas    int32  `json:"updated_replicas"`
+	ReadyReplicas      int32  `json:"ready_replicas"`
+	AvailableReplicas  int32  `json:"available_replicas"`
+	Paused             bool   `json:"paused"`
+	ProgressingStatus  string `json:"progressing_status"`
+	ProgressingReason  string `json:"progressing_reason"`
+	ProgressingMessage string `json:"progressing_message"`
+	DeadlineExceeded   bool   `json:"deadline_exceeded"`
+	Done               bool   `json:"done"`
+	Message            string `json:"message"`
+}
+
+// GetDeploymentHistory 获取deployment的历史版本，按版本号倒序
+func (d *deployment) GetDeploymentHistory(client *kubernetes.Clientset, namespace, name string) ([]*DeploymentRevision, error) {
+	deployment, err := client.AppsV1().Deployments(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取deployment详情失败, " + err.Error()))
+		return nil, errors.New("获取deployment详情失败, " + err.Error())
+	}
+	replicaSets, err := d.getReplicaSets(client, deployment)
+	if err != nil {
+		return nil, err
+	}
+	currentRevision := getRevision(deployment)
+	revisions := make([]*DeploymentRevision, 0, len(replicaSets))
+	for _, replicaSet := range replicaSets {
+		revision := &DeploymentRevision{
+			Revision:          getRevision(&replicaSet),
+			ReplicaSet:        replicaSet.Name,
+			Images:            make([]string, 0, len(replicaSet.Spec.Template.Spec.Containers)),
+			ChangeCause:       replicaSet.Annotations[ChangeCauseAnnotation],
+			Replicas:          replicaSet.Status.Replicas,
+			CreationTimestamp: replicaSet.CreationTimestamp,
+		}
+		revision.Current = revision.Revision == currentRevision
+		for _, container := range replicaSet.Spec.Template.Spec.Containers {
+			revision.Images = append(revision.Images, container.Image)
+		}
+		revisions = append(revisions, revision)
+	}
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Revision > revisions[j].Revision
+	})
+	return revisions, nil
+}
+
+// DiffDeploymentRevisions 对比deployment两个版本的pod模板，忽略pod-template-hash标签
+func (d *deployment) DiffDeploymentRevisions(client *kubernetes.Clientset, namespace, name string, from, to int64) (*RevisionDiff, error) {
+	deployment, err := client.AppsV1().Deployments(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取deployment详情失败, " + err.Error()))
+		return nil, errors.New("获取deployment详情失败, " + err.Error())
+	}
+	replicaSets, err := d.getReplicaSets(client, deployment)
+	if err != nil {
+		return nil, err
+	}
+	fromTemplate, err := d.getRevisionTemplate(replicaSets, from)
+	if err != nil {
+		return nil, err
+	}
+	toTemplate, err := d.getRevisionTemplate(replicaSets, to)
+	if err != nil {
+		return nil, err
+	}
+	fromFields, err := flattenObject(fromTemplate)
+	if err != nil {
+		return nil, err
+	}
+	toFields, err := flattenObject(toTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]*TemplateChange, 0)
+	for path, value := range fromFields {
+		toValue, ok := toFields[path]
+		if !ok {
+			changes = append(changes, &TemplateChange{Path: path, From: value})
+		} else if !reflect.DeepEqual(value, toValue) {
+			changes = append(changes, &TemplateChange{Path: path, From: value, To: toValue})
+		}
+	}
+	for path, value := range toFields {
+		if _, ok := fromFields[path]; !ok {
+			changes = append(changes, &TemplateChange{Path: path, To: value})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return &RevisionDiff{
+		From:    from,
+		To:      to,
+		Changes: changes,
+	}, nil
+}
+
+// RollbackDeployment 将deployment的pod模板回滚为指定版本，等同于kubectl rollout undo --to-revision
+// 回滚会生成一个新的版本号，原版本的change-cause会一并恢复
+func (d *deployment) RollbackDeployment(client *kubernetes.Clientset, namespace, name string, revision int64) (err error) {
+	deployment, err := client.AppsV1().Deployments(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取deployment详情失败, " + err.Error()))
+		return errors.New("获取deployment详情失败, " + err.Error())
+	}
+	if deployment.Spec.Paused {
+		return errors.New("回滚deployment失败, deployment已暂停，请先恢复")
+	}
+	replicaSets, err := d.getReplicaSets(client, deployment)
+	if err != nil {
+		return err
+	}
+	template, err := d.getRevisionTemplate(replicaSets, revision)
+	if err != nil {
+		return err
+	}
+
+	// 使用json patch整体替换pod模板，strategic merge patch会保留新版本中多出的字段
+	patches := []map[string]interface{}{
+		{"op": "replace", "path": "/spec/template", "value": template},
+	}
+	changeCause := ""
+	for _, replicaSet := range replicaSets {
+		if getRevision(&replicaSet) == revision {
+			changeCause = replicaSet.Annotations[ChangeCauseAnnotation]
+		}
+	}
+	if changeCause != "" {
+		if deployment.Annotations == nil {
+			patches = append(patches, map[string]interface{}{
+				"op": "add", "path": "/metadata/annotations", "value": map[string]string{},
+			})
+		}
+		patches = append(patches, map[string]interface{}{
+			"op": "add", "path": "/metadata/annotations/" + escapeJSONPointer(ChangeCauseAnnotation), "value": changeCause,
+		})
+	}
+	patchBytes, err := json.Marshal(patches)
+	if err != nil {
+		logger.Error(errors.New("序列化patchData失败, " + err.Error()))
+		return errors.New("序列化patchData失败, " + err.Error())
+	}
+	_, err = client.AppsV1().Deployments(namespace).Patch(context.TODO(), name, types.JSONPatchType, patchBytes, metav1.PatchOptions{})
+	if err != nil {
+		logger.Error(errors.New("回滚deployment失败, " + err.Error()))
+		return errors.New("回滚deployment失败, " + err.Error())
+	}
+	return nil
+}
+
+// GetRolloutStatus 获取deployment当前的发布进度
+func (d *deployment) GetRolloutStatus(client *kubernetes.Clientset, namespace, name string) (*RolloutStatus, error) {
+	deployment, err := client.AppsV1().Deployments(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取deployment详情失败, " + err.Error()))
+		return nil, errors.New("获取deployment详情失败, " + err.Error())
+	}
+	return d.toRolloutStatus(deployment), nil
+}
+
+// WaitRolloutStatus 监听deployment的发布进度，直到发布完成、超过deadline、发布被暂停或ctx结束
+// 暂停的deployment不会继续发布，与kubectl rollout status一致直接返回当前进度
+// 每次进度变化都会调用onChange，返回最后一次的进度；ctx结束时同时返回ctx的错误
+func (d *deployment) WaitRolloutStatus(ctx context.Context, client *kubernetes.Clientset, namespace, name string, onChange func(*RolloutStatus)) (*RolloutStatus, error) {
+	var last *RolloutStatus
+	fieldSelector := fields.OneTermEqualSelector("metadata.name", name).String()
+	for {
+		deploymentList, err := client.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{FieldSelector: fieldSelector})
+		if err != nil {
+			if ctx.Err() != nil {
+				return last, ctx.Err()
+			}
+			logger.Error(errors.New("获取deployment详情失败, " + err.Error()))
+			return last, errors.New("获取deployment详情失败, " + err.Error())
+		}
+		if len(deploymentList.Items) == 0 {
+			return last, errors.New("deployment " + name + "不存在")
+		}
+		last = d.notifyRolloutStatus(&deploymentList.Items[0], last, onChange)
+		if last.Done || last.DeadlineExceeded || last.Paused {
+			return last, nil
+		}
+
+		watcher, err := client.AppsV1().Deployments(namespace).Watch(ctx, metav1.ListOptions{
+			FieldSelector:   fieldSelector,
+			ResourceVersion: deploymentList.ResourceVersion,
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return last, ctx.Err()
+			}
+			logger.Error(errors.New("监听deployment失败, " + err.Error()))
+			return last, errors.New("监听deployment失败, " + err.Error())
+		}
+		for event := range watcher.ResultChan() {
+			if event.Type == watch.Deleted {
+				watcher.Stop()
+				return last, errors.New("deployment " + name + "已被删除")
+			}
+			deployment, ok := event.Object.(*appsv1.Deployment)
+			if !ok {
+				continue
+			}
+			last = d.notifyRolloutStatus(deployment, last, onChange)
+			if last.Done || last.DeadlineExceeded || last.Paused {
+				watcher.Stop()
+				return last, nil
+			}
+		}
+		watcher.Stop()
+		// watch被apiserver关闭时重新获取最新状态并继续监听
+		if ctx.Err() != nil {
+			return last, ctx.Err()
+		}
+	}
+}
+
+// PauseDeployment 暂停或恢复deployment的发布，paused为true时暂停
+// 暂停期间对pod模板的修改不会触发新的发布
+func (d *deployment) PauseDeployment(client *kubernetes.Clientset, namespace, name string, paused bool) (err error) {
+	patchData := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"paused": paused,
+		},
+	}
+	patchBytes, err := json.Marshal(patchData)
+	if err != nil {
+		logger.Error(errors.New("序列化patchData失败, " + err.Error()))
+		return errors.New("序列化patchData失败, " + err.Error())
+	}
+	_, err = client.AppsV1().Deployments(namespace).Patch(context.TODO(), name, types.StrategicMergePatchType, patchBytes, metav1.PatchOptions{})
+	if err != nil {
+		logger.Error(errors.New("设置deployment暂停状态失败, " + err.Error()))
+		return errors.New("设置deployment暂停状态失败, " + err.Error())
+	}
+	return nil
+}
+
+// notifyRolloutStatus 计算最新的发布进度，与上一次不同时调用onChange
+func (d *deployment) notifyRolloutStatus(deployment *appsv1.Deployment, last *RolloutStatus, onChange func(*RolloutStatus)) *RolloutStatus {
+	status := d.toRolloutStatus(deployment)
+	if onChange != nil && (last == nil || *last != *status) {
+		onChange(status)
+	}
+	return status
+}
+
+// toRolloutStatus 根据deployment的status计算发布进度
+func (d *deployment) toRolloutStatus(deployment *appsv1.Deployment) *RolloutStatus {
+	status := &RolloutStatus{
+		Revision:          getRevision(deployment),
+		Replicas:          deployment.Status.Replicas,
+		UpdatedReplicas:   deployment.Status.UpdatedReplicas,
+		ReadyReplicas:     deployment.Status.ReadyReplicas,
+		AvailableReplicas: deployment.Status.AvailableReplicas,
+		Paused:            deployment.Spec.Paused,
+	}
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type != appsv1.DeploymentProgressing {
+			continue
+		}
+		status.ProgressingStatus = string(condition.Status)
+		status.ProgressingReason = condition.Reason
+		status.ProgressingMessage = condition.Message
+		// ProgressDeadlineExceeded为deployment controller设置的固定reason
+		status.DeadlineExceeded = condition.Reason == "ProgressDeadlineExceeded"
+	}
+
+	desired := int32(1)
+	if deployment.Spec.Replicas != nil {
+		desired = *deployment.Spec.Replicas
+	}
+	switch {
+	case deployment.Generation > deployment.Status.ObservedGeneration:
+		status.Message = "等待deployment controller处理最新的配置"
+	case status.Paused:
+		status.Message = fmt.Sprintf("deployment %s 的发布已暂停，恢复后才会继续发布", deployment.Name)
+	case status.DeadlineExceeded:
+		status.Message = fmt.Sprintf("deployment %s 超过了发布的最长时间(progressDeadlineSeconds)", deployment.Name)
+	case status.UpdatedReplicas < desired:
+		status.Message = fmt.Sprintf("等待发布完成: %d/%d 个新副本已更新", status.UpdatedReplicas, desired)
+	case status.Replicas > status.UpdatedReplicas:
+		status.Message = fmt.Sprintf("等待发布完成: %d 个旧副本等待终止", status.Replicas-status.UpdatedReplicas)
+	case status.AvailableReplicas < status.UpdatedReplicas:
+		status.Message = fmt.Sprintf("等待发布完成: %d/%d 个新副本可用", status.AvailableReplicas, status.UpdatedReplicas)
+	default:
+		status.Done = true
+		status.Message = fmt.Sprintf("deployment %s 发布完成", deployment.Name)
+	}
+	return status
+}
+
+// getReplicaSets 获取deployment控制的replicaset
+func (d *deployment) getReplicaSets(client *kubernetes.Clientset, deployment *appsv1.Deployment) ([]appsv1.ReplicaSet, error) {
+	replicaSetList, err := client.AppsV1().ReplicaSets(deployment.Namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取ReplicaSet列表失败, " + err.Error()))
+		return nil, errors.New("获取ReplicaSet列表失败, " + err.Error())
+	}
+	replicaSets := make([]appsv1.ReplicaSet, 0)
+	for i := range replicaSetList.Items {
+		if metav1.IsControlledBy(&replicaSetList.Items[i], deployment) {
+			replicaSets = append(replicaSets, replicaSetList.Items[i])
+		}
+	}
+	return replicaSets, nil
+}
+
+// getRevisionTemplate 获取指定版本replicaset的pod模板，去掉replicaset自动添加的pod-template-hash标签
+func (d *deployment) getRevisionTemplate(replicaSets []appsv1.ReplicaSet, revision int64) (*corev1.PodTemplateSpec, error) {
+	for _, replicaSet := range replicaSets {
+		if getRevision(&replicaSet) != revision {
+			continue
+		}
+		template := replicaSet.Spec.Template.DeepCopy()
+		delete(template.Labels, appsv1.DefaultDeploymentUniqueLabelKey)
+		return template, nil
+	}
+	return nil, errors.New("版本" + strconv.FormatInt(revision, 10) + "不存在")
+}
+
+// getRevision 获取对象上记录的版本号，没有记录时返回0
+func getRevision(object metav1.Object) int64 {
+	revision, err := strconv.ParseInt(object.GetAnnotations()[RevisionAnnotation], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return revision
+}
+
+// escapeJSONPointer 转义json patch路径中的~和/
+func escapeJSONPointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+// flattenObject 将对象展开为"字段路径 -> 值"的形式，用于对比两个对象的差异
+func flattenObject(object interface{}) (map[string]interface{}, error) {
+	data, err := runtime.DefaultUnstructuredConverter.ToUnstructured(object)
+	if err != nil {
+		logger.Error(errors.New("转换对象失败, " + err.Error()))
+		return nil, errors.New("转换对象失败, " + err.Error())
+	}
+	fields := make(map[string]interface{})
+	flattenValue("", data, fields)
+	return fields, nil
+}
+
+func flattenValue(path string, value interface{}, fields map[string]interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if path == "" {
+				flattenValue(key, item, fields)
+			} else {
+				flattenValue(path+"."+key, item, fields)
+			}
+		}
+	case []interface{}:
+		for index, item := range v {
+			// 容器、环境变量、端口等列表元素有name字段，用name定位可以避免顺序变化导致的误判
+			if element, ok := item.(map[string]interface{}); ok {
+				if name, ok := element["name"].(string); ok && name != "" {
+					flattenValue(fmt.Sprintf("%s[name=%s]", path, name), item, fields)
+					continue
+				}
+			}
+			flattenValue(fmt.Sprintf("%s[%d]", path, index), item, fields)
+		}
+	default:
+		fields[path] = value
+	}
+}
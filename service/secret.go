@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aryming/logger"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  secret.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-04-11 14:30
+ */
+
+var Secret secret
+
+type secret struct{}
+
+// SecretMask 列表和详情中secret的值统一替换为该掩码
+// 更新时若某个key的值仍为该掩码，则保留原值不变
+const SecretMask = "******"
+
+// lastAppliedAnnotation kubectl apply记录的注解中包含明文的secret数据，返回前需要去除
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// SecretView 定义返回给前端的secret，Data为掩码后(或reveal后)的明文值
+type SecretView struct {
+	metav1.ObjectMeta `json:"metadata"`
+	Type              corev1.SecretType `json:"type"`
+	Data              map[string]string `json:"data"`
+}
+
+// SecretsResp 定义列表的返回内容，Items是掩码后的secret元素列表，Total为secret元素数量
+type SecretsResp struct {
+	Items []*SecretView `json:"items"`
+	Total int           `json:"total"`
+}
+
+// SecretCreate 定义创建secret需要的参数属性，Data为明文的key/value，base64编码由服务端处理
+type SecretCreate struct {
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace"`
+	Type      string            `json:"type"`
+	Labels    map[string]string `json:"labels"`
+	Data      map[string]string `json:"data"`
+	Cluster   string            `json:"cluster"`
+}
+
+// GetSecrets 获取secret列表，支持过滤、排序、分页，返回的值均已掩码
+func (s *secret) GetSecrets(client *kubernetes.Clientset, filterName, namespace string, limit, page int) (*SecretsResp, error) {
+	secretList, err := client.CoreV1().Secrets(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取Secret列表失败, " + err.Error()))
+		return nil, errors.New("获取Secret列表失败, " + err.Error())
+	}
+	selectableData := &dataSelector{
+		GenericDateSelect: s.toCells(secretList.Items),
+		dataSelectQuery: &DataSelectQuery{
+			FilterQuery: &FilterQuery{Name: filterName},
+			PaginationQuery: &PaginationQuery{
+				Limit: limit,
+				Page:  page,
+			},
+		},
+	}
+	filtered := selectableData.Filter()
+	total := len(filtered.GenericDateSelect)
+	data := filtered.Sort().Paginate()
+
+	secrets := s.fromCells(data.GenericDateSelect)
+	items := make([]*SecretView, 0, len(secrets))
+	for i := range secrets {
+		items = append(items, s.toView(&secrets[i], false))
+	}
+	return &SecretsResp{
+		Items: items,
+		Total: total,
+	}, nil
+}
+
+// GetSecretDetail 获取secret详情，返回的值已掩码
+func (s *secret) GetSecretDetail(client *kubernetes.Clientset, namespace, name string) (*SecretView, error) {
+	secret, err := s.getSecret(client, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	return s.toView(secret, false), nil
+}
+
+// RevealSecret 获取secret的明文值，key不为空时只返回该key的值
+func (s *secret) RevealSecret(client *kubernetes.Clientset, namespace, name, key string) (*SecretView, error) {
+	secret, err := s.getSecret(client, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	view := s.toView(secret, true)
+	if key == "" {
+		return view, nil
+	}
+	value, ok := view.Data[key]
+	if !ok {
+		return nil, errors.New("Secret中不存在key: " + key)
+	}
+	view.Data = map[string]string{key: value}
+	return view, nil
+}
+
+// CreateSecret 创建secret,接收SecretCreate对象
+func (s *secret) CreateSecret(client *kubernetes.Clientset, data *SecretCreate) (err error) {
+	secretType := corev1.SecretType(data.Type)
+	if secretType == "" {
+		secretType = corev1.SecretTypeOpaque
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      data.Name,
+			Namespace: data.Namespace,
+			Labels:    data.Labels,
+		},
+		Type: secretType,
+		Data: s.encode(data.Data, nil),
+	}
+	_, err = client.CoreV1().Secrets(data.Namespace).Create(context.TODO(), secret, metav1.CreateOptions{})
+	if err != nil {
+		logger.Error(errors.New("创建Secret失败, " + err.Error()))
+		return errors.New("创建Secret失败, " + err.Error())
+	}
+	return nil
+}
+
+// UpdateSecret 更新secret的数据，data为明文的key/value
+// 值为SecretMask的key保留原值，data中不存在的key会被删除
+func (s *secret) UpdateSecret(client *kubernetes.Clientset, namespace, name string, data map[string]string) (err error) {
+	secret, err := s.getSecret(client, namespace, name)
+	if err != nil {
+		return err
+	}
+	secret.Data = s.encode(data, secret.Data)
+	secret.StringData = nil
+	_, err = client.CoreV1().Secrets(namespace).Update(context.TODO(), secret, metav1.UpdateOptions{})
+	if err != nil {
+		logger.Error(errors.New("更新Secret失败, " + err.Error()))
+		return errors.New("更新Secret失败, " + err.Error())
+	}
+	return nil
+}
+
+// DeleteSecret 删除secret
+func (s *secret) DeleteSecret(client *kubernetes.Clientset, name, namespace string) (err error) {
+	err = client.CoreV1().Secrets(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+	if err != nil {
+		logger.Error(errors.New("删除Secret失败, " + err.Error()))
+		return errors.New("删除Secret失败, " + err.Error())
+	}
+	return nil
+}
+
+func (s *secret) getSecret(client *kubernetes.Clientset, namespace, name string) (*corev1.Secret, error) {
+	secret, err := client.CoreV1().Secrets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取Secret详情失败, " + err.Error()))
+		return nil, errors.New("获取Secret详情失败, " + err.Error())
+	}
+	return secret, nil
+}
+
+// encode 将明文的key/value转换为secret的Data，序列化时client-go会自动做base64编码
+// old不为空时，值为SecretMask的key沿用old中的原值
+func (s *secret) encode(data map[string]string, old map[string][]byte) map[string][]byte {
+	encoded := make(map[string][]byte, len(data))
+	for key, value := range data {
+		if value == SecretMask {
+			if oldValue, ok := old[key]; ok {
+				encoded[key] = oldValue
+				continue
+			}
+		}
+		encoded[key] = []byte(value)
+	}
+	return encoded
+}
+
+// toView 将secret转换为SecretView，reveal为false时所有值替换为SecretMask
+func (s *secret) toView(secret *corev1.Secret, reveal bool) *SecretView {
+	view := &SecretView{
+		ObjectMeta: *secret.ObjectMeta.DeepCopy(),
+		Type:       secret.Type,
+		Data:       make(map[string]string, len(secret.Data)),
+	}
+	delete(view.Annotations, lastAppliedAnnotation)
+	view.ManagedFields = nil
+	for key, value := range secret.Data {
+		if reveal {
+			view.Data[key] = string(value)
+		} else {
+			view.Data[key] = SecretMask
+		}
+	}
+	return view
+}
+
+func (s *secret) toCells(std []corev1.Secret) []DataCell {
+	cells := make([]DataCell, len(std))
+	for i := range std {
+		cells[i] = secretCell(std[i])
+	}
+	return cells
+}
+
+func (s *secret) fromCells(cells []DataCell) []corev1.Secret {
+	std := make([]corev1.Secret, len(cells))
+	for i := range cells {
+		std[i] = corev1.Secret(cells[i].(secretCell))
+	}
+	return std
+}
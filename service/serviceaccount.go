@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aryming/logger"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  serviceaccount.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-04-25 10:00
+ */
+
+var ServiceAccount serviceAccount
+
+type serviceAccount struct{}
+
+// ServiceAccountsResp 定义列表的返回内容，Items是serviceaccount元素列表，Total为serviceaccount元素数量
+type ServiceAccountsResp struct {
+	Items []corev1.ServiceAccount `json:"items"`
+	Total int                     `json:"total"`
+}
+
+// GetServiceAccounts 获取serviceaccount列表，支持过滤、排序、分页
+func (s *serviceAccount) GetServiceAccounts(client *kubernetes.Clientset, filterName, namespace string, limit, page int) (*ServiceAccountsResp, error) {
+	serviceAccountList, err := client.CoreV1().ServiceAccounts(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取ServiceAccount列表失败, " + err.Error()))
+		return nil, errors.New("获取ServiceAccount列表失败, " + err.Error())
+	}
+	selectableData := &dataSelector{
+		GenericDateSelect: s.toCells(serviceAccountList.Items),
+		dataSelectQuery: &DataSelectQuery{
+			FilterQuery: &FilterQuery{Name: filterName},
+			PaginationQuery: &PaginationQuery{
+				Limit: limit,
+				Page:  page,
+			},
+		},
+	}
+	filtered := selectableData.Filter()
+	total := len(filtered.GenericDateSelect)
+	data := filtered.Sort().Paginate()
+
+	items := s.fromCells(data.GenericDateSelect)
+	return &ServiceAccountsResp{
+		Items: items,
+		Total: total,
+	}, nil
+}
+
+// GetServiceAccountDetail 获取serviceaccount详情
+func (s *serviceAccount) GetServiceAccountDetail(client *kubernetes.Clientset, namespace, name string) (*corev1.ServiceAccount, error) {
+	serviceAccount, err := client.CoreV1().ServiceAccounts(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取ServiceAccount详情失败, " + err.Error()))
+		return nil, errors.New("获取ServiceAccount详情失败, " + err.Error())
+	}
+	return serviceAccount, nil
+}
+
+func (s *serviceAccount) toCells(std []corev1.ServiceAccount) []DataCell {
+	cells := make([]DataCell, len(std))
+	for i := range std {
+		cells[i] = serviceAccountCell(std[i])
+	}
+	return cells
+}
+
+func (s *serviceAccount) fromCells(cells []DataCell) []corev1.ServiceAccount {
+	std := make([]corev1.ServiceAccount, len(cells))
+	for i := range cells {
+		std[i] = corev1.ServiceAccount(cells[i].(serviceAccountCell))
+	}
+	return std
+}
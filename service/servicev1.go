@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/aryming/logger"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  servicev1.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-04-07 10:30
+ */
+
+// Servicev1 为了避免与包名service混淆，k8s的Service资源命名为servicev1
+var Servicev1 servicev1
+
+type servicev1 struct{}
+
+// ServicesResp 定义列表的返回内容，Items是service元素列表，Total为service元素数量
+type ServicesResp struct {
+	Items []corev1.Service `json:"items"`
+	Total int              `json:"total"`
+}
+
+// ServiceCreate 定义创建service需要的参数属性，Labels同时作为service的selector
+type ServiceCreate struct {
+	Name          string            `json:"name"`
+	Namespace     string            `json:"namespace"`
+	Type          string            `json:"type"`
+	ContainerPort int32             `json:"container_port"`
+	Port          int32             `json:"port"`
+	NodePort      int32             `json:"node_port"`
+	Labels        map[string]string `json:"labels"`
+	Cluster       string            `json:"cluster"`
+}
+
+// ServiceEndpoint 定义service后端endpoint的信息，Ready为false表示该地址在NotReadyAddresses中
+type ServiceEndpoint struct {
+	IP       string `json:"ip"`
+	NodeName string `json:"node_name"`
+	PodName  string `json:"pod_name"`
+	Ready    bool   `json:"ready"`
+}
+
+// ServiceDetail 定义service详情的返回内容
+// Pods为根据selector解析出的pod，Endpoints为endpoints对象中的ready/not-ready地址
+type ServiceDetail struct {
+	Service           *corev1.Service    `json:"service"`
+	Pods              []corev1.Pod       `json:"pods"`
+	Endpoints         []*ServiceEndpoint `json:"endpoints"`
+	ReadyEndpoints    int                `json:"ready_endpoints"`
+	NotReadyEndpoints int                `json:"not_ready_endpoints"`
+}
+
+// GetServices 获取service列表，支持过滤、排序、分页
+func (s *servicev1) GetServices(client *kubernetes.Clientset, filterName, namespace string, limit, page int) (*ServicesResp, error) {
+	serviceList, err := client.CoreV1().Services(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取Service列表失败, " + err.Error()))
+		return nil, errors.New("获取Service列表失败, " + err.Error())
+	}
+	selectableData := &dataSelector{
+		GenericDateSelect: s.toCells(serviceList.Items),
+		dataSelectQuery: &DataSelectQuery{
+			FilterQuery: &FilterQuery{Name: filterName},
+			PaginationQuery: &PaginationQuery{
+				Limit: limit,
+				Page:  page,
+			},
+		},
+	}
+	filtered := selectableData.Filter()
+	total := len(filtered.GenericDateSelect)
+	data := filtered.Sort().Paginate()
+
+	services := s.fromCells(data.GenericDateSelect)
+	return &ServicesResp{
+		Items: services,
+		Total: total,
+	}, nil
+}
+
+// GetServiceDetail 获取service详情，同时解析selector关联的pod以及endpoints的就绪情况
+func (s *servicev1) GetServiceDetail(client *kubernetes.Clientset, namespace, name string) (*ServiceDetail, error) {
+	svc, err := client.CoreV1().Services(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取Service详情失败, " + err.Error()))
+		return nil, errors.New("获取Service详情失败, " + err.Error())
+	}
+	detail := &ServiceDetail{
+		Service:   svc,
+		Pods:      make([]corev1.Pod, 0),
+		Endpoints: make([]*ServiceEndpoint, 0),
+	}
+	// 没有selector的service(如ExternalName或手动维护endpoints)不解析pod
+	if len(svc.Spec.Selector) > 0 {
+		pods, err := Pod.GetPodsBySelector(client, namespace, labels.SelectorFromSet(svc.Spec.Selector).String())
+		if err != nil {
+			return nil, err
+		}
+		detail.Pods = pods
+	}
+
+	endpoints, err := client.CoreV1().Endpoints(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		// endpoints不存在时直接返回，此时service没有任何后端
+		if k8serrors.IsNotFound(err) {
+			return detail, nil
+		}
+		logger.Error(errors.New("获取Endpoints失败, " + err.Error()))
+		return nil, errors.New("获取Endpoints失败, " + err.Error())
+	}
+	for _, subset := range endpoints.Subsets {
+		for _, address := range subset.Addresses {
+			detail.Endpoints = append(detail.Endpoints, s.toServiceEndpoint(address, true))
+			detail.ReadyEndpoints++
+		}
+		for _, address := range subset.NotReadyAddresses {
+			detail.Endpoints = append(detail.Endpoints, s.toServiceEndpoint(address, false))
+			detail.NotReadyEndpoints++
+		}
+	}
+	return detail, nil
+}
+
+// CreateService 创建service,接收ServiceCreate对象
+func (s *servicev1) CreateService(client *kubernetes.Clientset, data *ServiceCreate) (err error) {
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      data.Name,
+			Namespace: data.Namespace,
+			Labels:    data.Labels,
+		},
+		Spec: corev1.ServiceSpec{
+			Type: corev1.ServiceType(data.Type),
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "http",
+					Port:       data.Port,
+					Protocol:   corev1.ProtocolTCP,
+					TargetPort: intstr.FromInt32(data.ContainerPort),
+				},
+			},
+			Selector: data.Labels,
+		},
+	}
+	//NodePort类型且指定了nodePort时才设置，否则由集群自动分配
+	if data.NodePort != 0 && data.Type == string(corev1.ServiceTypeNodePort) {
+		svc.Spec.Ports[0].NodePort = data.NodePort
+	}
+	_, err = client.CoreV1().Services(data.Namespace).Create(context.TODO(), svc, metav1.CreateOptions{})
+	if err != nil {
+		logger.Error(errors.New("创建Service失败, " + err.Error()))
+		return errors.New("创建Service失败, " + err.Error())
+	}
+	return nil
+}
+
+// UpdateService 更新service，content参数是请求中传入的service对象的json数据
+func (s *servicev1) UpdateService(client *kubernetes.Clientset, namespace, content string) (err error) {
+	var svc = &corev1.Service{}
+	err = json.Unmarshal([]byte(content), svc)
+	if err != nil {
+		logger.Error(errors.New("反序列化失败, " + err.Error()))
+		return errors.New("反序列化失败, " + err.Error())
+	}
+	_, err = client.CoreV1().Services(namespace).Update(context.TODO(), svc, metav1.UpdateOptions{})
+	if err != nil {
+		logger.Error(errors.New("更新Service失败, " + err.Error()))
+		return errors.New("更新Service失败, " + err.Error())
+	}
+	return nil
+}
+
+// DeleteService 删除service
+func (s *servicev1) DeleteService(client *kubernetes.Clientset, name, namespace string) (err error) {
+	err = client.CoreV1().Services(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+	if err != nil {
+		logger.Error(errors.New("删除Service失败, " + err.Error()))
+		return errors.New("删除Service失败, " + err.Error())
+	}
+	return nil
+}
+
+// toServiceEndpoint 将endpoints中的地址转换为ServiceEndpoint
+func (s *servicev1) toServiceEndpoint(address corev1.EndpointAddress, ready bool) *ServiceEndpoint {
+	endpoint := &ServiceEndpoint{
+		IP:    address.IP,
+		Ready: ready,
+	}
+	if address.NodeName != nil {
+		endpoint.NodeName = *address.NodeName
+	}
+	if address.TargetRef != nil && address.TargetRef.Kind == "Pod" {
+		endpoint.PodName = address.TargetRef.Name
+	}
+	return endpoint
+}
+
+func (s *servicev1) toCells(std []corev1.Service) []DataCell {
+	cells := make([]DataCell, len(std))
+	for i := range std {
+		cells[i] = serviceCell(std[i])
+	}
+	return cells
+}
+
+func (s *servicev1) fromCells(cells []DataCell) []corev1.Service {
+	std := make([]corev1.Service, len(cells))
+	for i := range cells {
+		std[i] = corev1.Service(cells[i].(serviceCell))
+	}
+	return std
+}
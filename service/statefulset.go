@@ -0,0 +1,377 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aryming/logger"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  statefulset.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-04-02 10:12
+ */
+
+var StatefulSet statefulSet
+
+type statefulSet struct{}
+
+// StatefulSetsResp 定义列表的返回内容，Items是statefulset元素列表，Total为statefulset元素数量
+type StatefulSetsResp struct {
+	Items []appsv1.StatefulSet `json:"items"`
+	Total int                  `json:"total"`
+}
+
+// StatefulSetCreate 定义创建statefulset需要的参数属性
+// ServiceName为statefulset关联的headless service名称，StorageSize不为空时创建volumeClaimTemplates
+type StatefulSetCreate struct {
+	Name          string            `json:"name"`
+	Namespace     string            `json:"namespace"`
+	Replicas      int32             `json:"replicas"`
+	Image         string            `json:"image"`
+	Labels        map[string]string `json:"labels"`
+	Cpu           string            `json:"cpu"`
+	Memory        string            `json:"memory"`
+	ContainerPort int32             `json:"container_port"`
+	ServiceName   string            `json:"service_name"`
+	HealthCheck   bool              `json:"health_check"`
+	HealthPath    string            `json:"health_path"`
+	StorageClass  string            `json:"storage_class"`
+	StorageSize   string            `json:"storage_size"`
+	MountPath     string            `json:"mount_path"`
+	Cluster       string            `json:"cluster"`
+}
+
+// StatefulSetPod 定义statefulset下pod的信息，按序号(ordinal)排列
+type StatefulSetPod struct {
+	Ordinal  int    `json:"ordinal"`
+	Name     string `json:"name"`
+	Phase    string `json:"phase"`
+	Ready    bool   `json:"ready"`
+	Revision string `json:"revision"`
+	// Updated 表示pod是否已经是最新的updateRevision
+	Updated bool `json:"updated"`
+}
+
+// StatefulSetPodsResp 定义statefulset pod列表的返回内容
+type StatefulSetPodsResp struct {
+	CurrentRevision string            `json:"current_revision"`
+	UpdateRevision  string            `json:"update_revision"`
+	Partition       int32             `json:"partition"`
+	Pods            []*StatefulSetPod `json:"pods"`
+}
+
+// GetStatefulSets 获取statefulset列表，支持过滤、排序、分页
+func (s *statefulSet) GetStatefulSets(client *kubernetes.Clientset, filterName, namespace string, limit, page int) (*StatefulSetsResp, error) {
+	statefulSetList, err := client.AppsV1().StatefulSets(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取StatefulSet列表失败, " + err.Error()))
+		return nil, errors.New("获取StatefulSet列表失败, " + err.Error())
+	}
+	selectableData := &dataSelector{
+		GenericDateSelect: s.toCells(statefulSetList.Items),
+		dataSelectQuery: &DataSelectQuery{
+			FilterQuery: &FilterQuery{Name: filterName},
+			PaginationQuery: &PaginationQuery{
+				Limit: limit,
+				Page:  page,
+			},
+		},
+	}
+	filtered := selectableData.Filter()
+	total := len(filtered.GenericDateSelect)
+	data := filtered.Sort().Paginate()
+
+	statefulSets := s.fromCells(data.GenericDateSelect)
+	return &StatefulSetsResp{
+		Items: statefulSets,
+		Total: total,
+	}, nil
+}
+
+// GetStatefulSetDetail 获取statefulset详情
+func (s *statefulSet) GetStatefulSetDetail(client *kubernetes.Clientset, namespace, name string) (*appsv1.StatefulSet, error) {
+	statefulSet, err := client.AppsV1().StatefulSets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取StatefulSet详情失败, " + err.Error()))
+		return nil, errors.New("获取StatefulSet详情失败, " + err.Error())
+	}
+	return statefulSet, nil
+}
+
+// ScaleStatefulSet 设置statefulset副本数
+func (s *statefulSet) ScaleStatefulSet(client *kubernetes.Clientset, name, namespace string, scaleNum int) (replicas int32, err error) {
+	scale, err := client.AppsV1().StatefulSets(namespace).GetScale(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取StatefulSet副本数失败, " + err.Error()))
+		return 0, errors.New("获取StatefulSet副本数失败, " + err.Error())
+	}
+	scale.Spec.Replicas = int32(scaleNum)
+	newScale, err := client.AppsV1().StatefulSets(namespace).UpdateScale(context.TODO(), name, scale, metav1.UpdateOptions{})
+	if err != nil {
+		logger.Error(errors.New("更新StatefulSet副本数失败, " + err.Error()))
+		return 0, errors.New("更新StatefulSet副本数失败, " + err.Error())
+	}
+	return newScale.Spec.Replicas, nil
+}
+
+// CreateStatefulSet 创建statefulset,接收StatefulSetCreate对象
+func (s *statefulSet) CreateStatefulSet(client *kubernetes.Clientset, data *StatefulSetCreate) (err error) {
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      data.Name,
+			Namespace: data.Namespace,
+			Labels:    data.Labels,
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:    &data.Replicas,
+			ServiceName: data.ServiceName,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: data.Labels,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   data.Name,
+					Labels: data.Labels,
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:  data.Name,
+							Image: data.Image,
+						},
+					},
+				},
+			},
+		},
+		Status: appsv1.StatefulSetStatus{},
+	}
+	container := &statefulSet.Spec.Template.Spec.Containers[0]
+
+	//ContainerPort为0时不设置端口
+	if data.ContainerPort > 0 {
+		container.Ports = []corev1.ContainerPort{
+			{
+				Name:          "http",
+				Protocol:      corev1.ProtocolTCP,
+				ContainerPort: data.ContainerPort,
+			},
+		}
+	}
+
+	//cpu和memory不为空时设置容器的limit和request资源
+	resources, err := buildResourceList(&ResourceCreate{Cpu: data.Cpu, Memory: data.Memory})
+	if err != nil {
+		logger.Error(errors.New("创建StatefulSet失败, " + err.Error()))
+		return errors.New("创建StatefulSet失败, " + err.Error())
+	}
+	container.Resources.Limits = resources
+	container.Resources.Requests = resources
+
+	if data.HealthCheck {
+		if data.ContainerPort <= 0 {
+			return errors.New("创建StatefulSet失败, 开启健康检查时需要设置端口")
+		}
+		container.ReadinessProbe = buildHttpProbe(data.HealthPath, data.ContainerPort, 5)
+		container.LivenessProbe = buildHttpProbe(data.HealthPath, data.ContainerPort, 15)
+	}
+
+	//StorageSize不为空时，为每个pod创建独立的pvc，并挂载到MountPath
+	if data.StorageSize != "" {
+		storageSize, err := resource.ParseQuantity(data.StorageSize)
+		if err != nil {
+			logger.Error(errors.New("创建StatefulSet失败, 解析storage_size失败, " + err.Error()))
+			return errors.New("创建StatefulSet失败, 解析storage_size失败, " + err.Error())
+		}
+		pvc := corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "data",
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				Resources: corev1.VolumeResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: storageSize,
+					},
+				},
+			},
+		}
+		if data.StorageClass != "" {
+			pvc.Spec.StorageClassName = &data.StorageClass
+		}
+		statefulSet.Spec.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{pvc}
+		container.VolumeMounts = []corev1.VolumeMount{
+			{
+				Name:      "data",
+				MountPath: data.MountPath,
+			},
+		}
+	}
+
+	_, err = client.AppsV1().StatefulSets(data.Namespace).Create(context.TODO(), statefulSet, metav1.CreateOptions{})
+	if err != nil {
+		logger.Error(errors.New("创建StatefulSet失败, " + err.Error()))
+		return errors.New("创建StatefulSet失败, " + err.Error())
+	}
+	return nil
+}
+
+// RestartStatefulSet 重启statefulset，等同于kubectl rollout restart，返回重启生成的新版本
+func (s *statefulSet) RestartStatefulSet(client *kubernetes.Clientset, name, namespace string) (*WorkloadRevision, error) {
+	return Workload.Restart(client, WorkloadStatefulSet, namespace, name)
+}
+
+// UpdateStatefulSet 更新statefulset，content参数是请求中传入的statefulset对象的json数据
+func (s *statefulSet) UpdateStatefulSet(client *kubernetes.Clientset, namespace, content string) (err error) {
+	var statefulSet = &appsv1.StatefulSet{}
+	err = json.Unmarshal([]byte(content), statefulSet)
+	if err != nil {
+		logger.Error(errors.New("反序列化失败, " + err.Error()))
+		return errors.New("反序列化失败, " + err.Error())
+	}
+	_, err = client.AppsV1().StatefulSets(namespace).Update(context.TODO(), statefulSet, metav1.UpdateOptions{})
+	if err != nil {
+		logger.Error(errors.New("更新StatefulSet失败, " + err.Error()))
+		return errors.New("更新StatefulSet失败, " + err.Error())
+	}
+	return nil
+}
+
+// DeleteStatefulSet 删除statefulset
+func (s *statefulSet) DeleteStatefulSet(client *kubernetes.Clientset, name, namespace string) (err error) {
+	err = client.AppsV1().StatefulSets(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+	if err != nil {
+		logger.Error(errors.New("删除StatefulSet失败, " + err.Error()))
+		return errors.New("删除StatefulSet失败, " + err.Error())
+	}
+	return nil
+}
+
+// GetStatefulSetPods 获取statefulset下的pod，按照pod名称后缀的序号排序
+// 同时标记每个pod是否已经更新到updateRevision，用于观察分区滚动更新的进度
+func (s *statefulSet) GetStatefulSetPods(client *kubernetes.Clientset, name, namespace string) (*StatefulSetPodsResp, error) {
+	statefulSet, err := s.GetStatefulSetDetail(client, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	selector, err := metav1.LabelSelectorAsSelector(statefulSet.Spec.Selector)
+	if err != nil {
+		logger.Error(errors.New("解析StatefulSet selector失败, " + err.Error()))
+		return nil, errors.New("解析StatefulSet selector失败, " + err.Error())
+	}
+	podList, err := client.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		logger.Error(errors.New("获取StatefulSet的Pod列表失败, " + err.Error()))
+		return nil, errors.New("获取StatefulSet的Pod列表失败, " + err.Error())
+	}
+
+	resp := &StatefulSetPodsResp{
+		CurrentRevision: statefulSet.Status.CurrentRevision,
+		UpdateRevision:  statefulSet.Status.UpdateRevision,
+		Partition:       s.getPartition(statefulSet),
+		Pods:            make([]*StatefulSetPod, 0),
+	}
+	for _, item := range podList.Items {
+		// 只保留属于该statefulset的pod，序号从pod名称的"<name>-<ordinal>"中解析
+		if !metav1.IsControlledBy(&item, statefulSet) {
+			continue
+		}
+		ordinal, ok := s.getOrdinal(statefulSet.Name, item.Name)
+		if !ok {
+			continue
+		}
+		revision := item.Labels[appsv1.StatefulSetRevisionLabel]
+		resp.Pods = append(resp.Pods, &StatefulSetPod{
+			Ordinal:  ordinal,
+			Name:     item.Name,
+			Phase:    string(item.Status.Phase),
+			Ready:    isPodReady(&item),
+			Revision: revision,
+			Updated:  revision != "" && revision == statefulSet.Status.UpdateRevision,
+		})
+	}
+	sort.Slice(resp.Pods, func(i, j int) bool {
+		return resp.Pods[i].Ordinal < resp.Pods[j].Ordinal
+	})
+	return resp, nil
+}
+
+// UpdateStatefulSetPartition 设置statefulset滚动更新的分区
+// 只有序号大于等于partition的pod会被更新，partition为0时更新全部pod
+func (s *statefulSet) UpdateStatefulSetPartition(client *kubernetes.Clientset, name, namespace string, partition int32) (err error) {
+	if partition < 0 {
+		return errors.New("partition不能小于0")
+	}
+	patchData := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"updateStrategy": map[string]interface{}{
+				"type": appsv1.RollingUpdateStatefulSetStrategyType,
+				"rollingUpdate": map[string]interface{}{
+					"partition": partition,
+				},
+			},
+		},
+	}
+	patchBytes, err := json.Marshal(patchData)
+	if err != nil {
+		logger.Error(errors.New("序列化patchData失败, " + err.Error()))
+		return errors.New("序列化patchData失败, " + err.Error())
+	}
+	_, err = client.AppsV1().StatefulSets(namespace).Patch(context.TODO(), name, types.StrategicMergePatchType, patchBytes, metav1.PatchOptions{})
+	if err != nil {
+		logger.Error(errors.New("设置StatefulSet分区失败, " + err.Error()))
+		return errors.New("设置StatefulSet分区失败, " + err.Error())
+	}
+	return nil
+}
+
+// getPartition 获取statefulset当前的分区，未设置时为0
+func (s *statefulSet) getPartition(statefulSet *appsv1.StatefulSet) int32 {
+	rollingUpdate := statefulSet.Spec.UpdateStrategy.RollingUpdate
+	if rollingUpdate == nil || rollingUpdate.Partition == nil {
+		return 0
+	}
+	return *rollingUpdate.Partition
+}
+
+// getOrdinal 从pod名称中解析序号，pod名称格式为"<statefulset名称>-<序号>"
+func (s *statefulSet) getOrdinal(statefulSetName, podName string) (int, bool) {
+	suffix, found := strings.CutPrefix(podName, statefulSetName+"-")
+	if !found {
+		return 0, false
+	}
+	ordinal, err := strconv.Atoi(suffix)
+	if err != nil {
+		return 0, false
+	}
+	return ordinal, true
+}
+
+func (s *statefulSet) toCells(std []appsv1.StatefulSet) []DataCell {
+	cells := make([]DataCell, len(std))
+	for i := range std {
+		cells[i] = statefulSetCell(std[i])
+	}
+	return cells
+}
+
+func (s *statefulSet) fromCells(cells []DataCell) []appsv1.StatefulSet {
+	std := make([]appsv1.StatefulSet, len(cells))
+	for i := range cells {
+		std[i] = appsv1.StatefulSet(cells[i].(statefulSetCell))
+	}
+	return std
+}
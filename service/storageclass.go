@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aryming/logger"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  storageclass.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-04-23 15:45
+ */
+
+var StorageClass storageClass
+
+type storageClass struct{}
+
+// StorageClassesResp 定义列表的返回内容，Items是storageclass元素列表，Total为storageclass元素数量
+type StorageClassesResp struct {
+	Items []storagev1.StorageClass `json:"items"`
+	Total int                      `json:"total"`
+}
+
+// GetStorageClasses 获取storageclass列表，支持过滤、排序、分页
+func (s *storageClass) GetStorageClasses(client *kubernetes.Clientset, filterName string, limit, page int) (*StorageClassesResp, error) {
+	storageClassList, err := client.StorageV1().StorageClasses().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取StorageClass列表失败, " + err.Error()))
+		return nil, errors.New("获取StorageClass列表失败, " + err.Error())
+	}
+	selectableData := &dataSelector{
+		GenericDateSelect: s.toCells(storageClassList.Items),
+		dataSelectQuery: &DataSelectQuery{
+			FilterQuery: &FilterQuery{Name: filterName},
+			PaginationQuery: &PaginationQuery{
+				Limit: limit,
+				Page:  page,
+			},
+		},
+	}
+	filtered := selectableData.Filter()
+	total := len(filtered.GenericDateSelect)
+	data := filtered.Sort().Paginate()
+
+	storageClasses := s.fromCells(data.GenericDateSelect)
+	return &StorageClassesResp{
+		Items: storageClasses,
+		Total: total,
+	}, nil
+}
+
+// GetStorageClassDetail 获取storageclass详情
+func (s *storageClass) GetStorageClassDetail(client *kubernetes.Clientset, name string) (*storagev1.StorageClass, error) {
+	storageClass, err := client.StorageV1().StorageClasses().Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		logger.Error(errors.New("获取StorageClass详情失败, " + err.Error()))
+		return nil, errors.New("获取StorageClass详情失败, " + err.Error())
+	}
+	return storageClass, nil
+}
+
+// DeleteStorageClass 删除storageclass
+func (s *storageClass) DeleteStorageClass(client *kubernetes.Clientset, name string) (err error) {
+	err = client.StorageV1().StorageClasses().Delete(context.TODO(), name, metav1.DeleteOptions{})
+	if err != nil {
+		logger.Error(errors.New("删除StorageClass失败, " + err.Error()))
+		return errors.New("删除StorageClass失败, " + err.Error())
+	}
+	return nil
+}
+
+func (s *storageClass) toCells(std []storagev1.StorageClass) []DataCell {
+	cells := make([]DataCell, len(std))
+	for i := range std {
+		cells[i] = storageClassCell(std[i])
+	}
+	return cells
+}
+
+func (s *storageClass) fromCells(cells []DataCell) []storagev1.StorageClass {
+	std := make([]storagev1.StorageClass, len(cells))
+	for i := range cells {
+		std[i] = storagev1.StorageClass(cells[i].(storageClassCell))
+	}
+	return std
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aryming/logger"
+	"github.com/gorilla/websocket"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  terminal.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-05-08 10:00
+ */
+
+var Terminal terminal
+
+// terminal 记录所有打开的终端会话，服务关闭时统一断开
+type terminal struct {
+	sessions sync.Map
+}
+
+// 终端消息的类型
+const (
+	TerminalOpStdin  = "stdin"
+	TerminalOpStdout = "stdout"
+	TerminalOpResize = "resize"
+)
+
+// TerminalShells 未指定shell时依次尝试的shell，容器中不存在时尝试下一个
+var TerminalShells = []string{"bash", "sh"}
+
+// 向websocket写入消息的超时时间
+const terminalWriteTimeout = 10 * time.Second
+
+// TerminalMessage 定义终端websocket消息，消息体为json
+// 客户端发送stdin(Data为输入内容)和resize(Rows、Cols为终端大小)，服务端发送stdout(Data为输出内容)
+type TerminalMessage struct {
+	Op   string `json:"op"`
+	Data string `json:"data,omitempty"`
+	Rows uint16 `json:"rows,omitempty"`
+	Cols uint16 `json:"cols,omitempty"`
+}
+
+// TerminalSession 将websocket连接适配为exec的stdin、stdout和终端大小队列
+type TerminalSession struct {
+	conn     *websocket.Conn
+	sizeChan chan remotecommand.TerminalSize
+	done     chan struct{}
+	once     sync.Once
+	writeMu  sync.Mutex
+	pending  []byte
+}
+
+// NewTerminalSession 创建终端会话
+func (t *terminal) NewTerminalSession(conn *websocket.Conn) *TerminalSession {
+	session := &TerminalSession{
+		conn:     conn,
+		sizeChan: make(chan remotecommand.TerminalSize, 1),
+		done:     make(chan struct{}),
+	}
+	t.sessions.Store(session, struct{}{})
+	return session
+}
+
+// Read 读取客户端的输入作为stdin，resize消息写入终端大小队列
+func (s *TerminalSession) Read(p []byte) (int, error) {
+	for len(s.pending) == 0 {
+		_, data, err := s.conn.ReadMessage()
+		if err != nil {
+			s.Close(websocket.CloseNormalClosure, "")
+			return 0, err
+		}
+		msg := &TerminalMessage{}
+		if err := json.Unmarshal(data, msg); err != nil {
+			s.Close(websocket.CloseUnsupportedData, "终端消息格式错误")
+			return 0, errors.New("解析终端消息失败, " + err.Error())
+		}
+		switch msg.Op {
+		case TerminalOpStdin:
+			s.pending = []byte(msg.Data)
+		case TerminalOpResize:
+			// 只保留最新的大小，队列已满时丢弃旧值
+			select {
+			case <-s.sizeChan:
+			default:
+			}
+			s.sizeChan <- remotecommand.TerminalSize{Width: msg.Cols, Height: msg.Rows}
+		}
+	}
+	n := copy(p, s.pending)
+	s.pending = s.pending[n:]
+	return n, nil
+}
+
+// Write 将容器的输出以stdout消息发送给客户端
+func (s *TerminalSession) Write(p []byte) (int, error) {
+	if err := s.writeMessage(&TerminalMessage{Op: TerminalOpStdout, Data: string(p)}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Next 返回终端大小的变化，会话关闭时返回nil
+func (s *TerminalSession) Next() *remotecommand.TerminalSize {
+	select {
+	case size := <-s.sizeChan:
+		return &size
+	case <-s.done:
+		return nil
+	}
+}
+
+// Close 发送关闭帧并断开连接，reason为展示给客户端的原因，可重复调用
+func (s *TerminalSession) Close(code int, reason string) {
+	s.once.Do(func() {
+		Terminal.sessions.Delete(s)
+		close(s.done)
+		s.writeMu.Lock()
+		_ = s.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(terminalWriteTimeout))
+		s.writeMu.Unlock()
+		_ = s.conn.Close()
+	})
+}
+
+// writeMessage 向客户端写入消息，websocket不支持并发写
+func (s *TerminalSession) writeMessage(msg *TerminalMessage) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	_ = s.conn.SetWriteDeadline(time.Now().Add(terminalWriteTimeout))
+	return s.conn.WriteMessage(websocket.TextMessage, data)
+}
+
+// ExecPod 在容器中启动交互式shell并通过session收发数据，阻塞直到shell退出或连接断开
+// shell为空时依次尝试TerminalShells，容器中不存在的shell会自动尝试下一个
+func (t *terminal) ExecPod(ctx context.Context, client *kubernetes.Clientset, restConfig *rest.Config, namespace, podName, containerName, shell string, session *TerminalSession) error {
+	shells := TerminalShells
+	if shell != "" {
+		shells = []string{shell}
+	}
+	// 连接断开或服务关闭时结束exec
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-session.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	// 先以非交互方式探测可用的shell，再启动唯一一次交互式exec
+	// 失败的交互式exec会遗留读取session的协程，与下一次exec并发读取websocket
+	sh, err := t.detectShell(ctx, client, restConfig, namespace, podName, containerName, shells)
+	if err == nil {
+		err = t.exec(ctx, client, restConfig, namespace, podName, containerName, []string{sh}, session)
+	}
+	if err != nil {
+		logger.Error(errors.New("执行终端命令失败, " + err.Error()))
+		return errors.New("执行终端命令失败, " + err.Error())
+	}
+	return nil
+}
+
+// detectShell 依次在容器中执行 shell -c "exit 0"，返回第一个存在的shell
+// 最后一个shell不再探测，不存在时由交互式exec返回错误
+func (t *terminal) detectShell(ctx context.Context, client *kubernetes.Clientset, restConfig *rest.Config, namespace, podName, containerName string, shells []string) (string, error) {
+	for _, sh := range shells[:len(shells)-1] {
+		executor, err := newExecutor(client, restConfig, namespace, podName, &corev1.PodExecOptions{
+			Container: containerName,
+			Command:   []string{sh, "-c", "exit 0"},
+			Stdout:    true,
+			Stderr:    true,
+		})
+		if err != nil {
+			return "", err
+		}
+		err = executor.StreamWithContext(ctx, remotecommand.StreamOptions{Stdout: io.Discard, Stderr: io.Discard})
+		if err == nil {
+			return sh, nil
+		}
+		if !t.isShellNotFound(err) {
+			return "", err
+		}
+	}
+	return shells[len(shells)-1], nil
+}
+
+// Close 断开所有终端会话，在服务关闭时调用，被hijack的websocket连接不会被http.Server.Shutdown关闭
+func (t *terminal) Close() {
+	t.sessions.Range(func(key, value interface{}) bool {
+		key.(*TerminalSession).Close(websocket.CloseGoingAway, "服务正在关闭")
+		return true
+	})
+}
+
+// exec 在容器中以tty模式执行命令，通过session收发数据
+func (t *terminal) exec(ctx context.Context, client *kubernetes.Clientset, restConfig *rest.Config, namespace, podName, containerName string, command []string, session *TerminalSession) error {
+	executor, err := newExecutor(client, restConfig, namespace, podName, &corev1.PodExecOptions{
+		Container: containerName,
+		Command:   command,
+		Stdin:     true,
+		Stdout:    true,
+		Stderr:    true,
+		TTY:       true,
+	})
+	if err != nil {
+		return err
+	}
+	// tty模式下stderr合并到stdout
+	return executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdin:             session,
+		Stdout:            session,
+		Tty:               true,
+		TerminalSizeQueue: session,
+	})
+}
+
+// newExecutor 创建在容器中执行命令的executor，优先使用websocket协议，apiserver不支持时回退到SPDY
+func newExecutor(client *kubernetes.Clientset, restConfig *rest.Config, namespace, podName string, options *corev1.PodExecOptions) (remotecommand.Executor, error) {
+	req := client.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(podName).
+		SubResource("exec").
+		VersionedParams(options, scheme.ParameterCodec)
+	websocketExec, err := remotecommand.NewWebSocketExecutor(restConfig, "GET", req.URL().String())
+	if err != nil {
+		return nil, err
+	}
+	spdyExec, err := remotecommand.NewSPDYExecutor(restConfig, "POST", req.URL())
+	if err != nil {
+		return nil, err
+	}
+	return remotecommand.NewFallbackExecutor(websocketExec, spdyExec, func(err error) bool {
+		return httpstream.IsUpgradeFailure(err) || httpstream.IsHTTPSProxyError(err)
+	})
+}
+
+// isShellNotFound 判断错误是否为容器中不存在该shell
+// 不同容器运行时的表现不同，可能返回126/127退出码，也可能直接返回启动失败的错误
+func (t *terminal) isShellNotFound(err error) bool {
+	var exitErr utilexec.ExitError
+	if errors.As(err, &exitErr) && (exitErr.ExitStatus() == 126 || exitErr.ExitStatus() == 127) {
+		return true
+	}
+	return strings.Contains(err.Error(), "executable file not found") || strings.Contains(err.Error(), "no such file or directory")
+}
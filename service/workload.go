@@ -0,0 +1,331 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aryming/logger"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+)
+
+/**
+ * @Author: 南宫乘风
+ * @Description:
+ * @File:  workload.go
+ * @Email: 1794748404@qq.com
+ * @Date: 2025-05-06 10:00
+ */
+
+var Workload workload
+
+type workload struct{}
+
+// 支持set-image和重启的工作负载类型
+const (
+	WorkloadDeployment  = "deployment"
+	WorkloadStatefulSet = "statefulset"
+	WorkloadDaemonSet   = "daemonset"
+)
+
+// RestartedAtAnnotation kubectl rollout restart在pod模板上设置的注解，修改它会触发滚动更新
+const RestartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+// 修改工作负载后等待控制器生成新版本的时间
+const revisionWaitTimeout = 10 * time.Second
+
+// WorkloadRevision 定义工作负载修改后的版本
+// deployment的RevisionName为replicaset名称，statefulset和daemonset为ControllerRevision名称
+// 控制器在等待时间内未处理完成时Revision为修改前的版本，可通过发布进度接口继续观察
+type WorkloadRevision struct {
+	Kind         string `json:"kind"`
+	Name         string `json:"name"`
+	Namespace    string `json:"namespace"`
+	Revision     int64  `json:"revision"`
+	RevisionName string `json:"revision_name"`
+}
+
+// SetImage 修改工作负载中指定容器的镜像，images的key为容器名称(包括initContainer)，value为新镜像
+// 只patch指定的容器，同时记录change-cause注解，返回修改后的版本
+func (w *workload) SetImage(client *kubernetes.Clientset, kind, namespace, name string, images map[string]string, changeCause string) (*WorkloadRevision, error) {
+	if len(images) == 0 {
+		return nil, errors.New("修改镜像失败, 没有指定容器和镜像")
+	}
+	template, err := w.getPodTemplate(client, kind, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	// strategic merge patch按name合并容器，容器名不存在时会新增容器，需要提前校验
+	containers := make([]map[string]string, 0)
+	initContainers := make([]map[string]string, 0)
+	for _, container := range template.Spec.Containers {
+		if image, ok := images[container.Name]; ok {
+			containers = append(containers, map[string]string{"name": container.Name, "image": image})
+		}
+	}
+	for _, container := range template.Spec.InitContainers {
+		if image, ok := images[container.Name]; ok {
+			initContainers = append(initContainers, map[string]string{"name": container.Name, "image": image})
+		}
+	}
+	if len(containers)+len(initContainers) != len(images) {
+		return nil, errors.New("修改镜像失败, 容器不存在: " + strings.Join(w.missingContainers(template, images), ","))
+	}
+	if changeCause == "" {
+		pairs := make([]string, 0, len(images))
+		for container, image := range images {
+			pairs = append(pairs, container+"="+image)
+		}
+		sort.Strings(pairs)
+		changeCause = "set image " + strings.Join(pairs, " ")
+	}
+
+	podSpec := map[string]interface{}{}
+	if len(containers) > 0 {
+		podSpec["containers"] = containers
+	}
+	if len(initContainers) > 0 {
+		podSpec["initContainers"] = initContainers
+	}
+	patchData := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				ChangeCauseAnnotation: changeCause,
+			},
+		},
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": podSpec,
+			},
+		},
+	}
+	generation, err := w.patch(client, kind, namespace, name, patchData)
+	if err != nil {
+		logger.Error(errors.New("修改镜像失败, " + err.Error()))
+		return nil, errors.New("修改镜像失败, " + err.Error())
+	}
+	return w.waitRevision(client, kind, namespace, name, generation), nil
+}
+
+// Restart 重启工作负载，等同于kubectl rollout restart，返回重启生成的新版本
+// 通过修改pod模板上的kubectl.kubernetes.io/restartedAt注解触发滚动更新，不修改容器的配置
+func (w *workload) Restart(client *kubernetes.Clientset, kind, namespace, name string) (*WorkloadRevision, error) {
+	patchData := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"annotations": map[string]string{
+						RestartedAtAnnotation: time.Now().Format(time.RFC3339),
+					},
+				},
+			},
+		},
+	}
+	generation, err := w.patch(client, kind, namespace, name, patchData)
+	if err != nil {
+		logger.Error(errors.New("重启" + kind + "失败, " + err.Error()))
+		return nil, errors.New("重启" + kind + "失败, " + err.Error())
+	}
+	return w.waitRevision(client, kind, namespace, name, generation), nil
+}
+
+// GetSelector 获取工作负载的pod标签选择器，返回label selector字符串
+func (w *workload) GetSelector(client *kubernetes.Clientset, kind, namespace, name string) (string, error) {
+	var (
+		labelSelector *metav1.LabelSelector
+		err           error
+	)
+	switch kind {
+	case WorkloadDeployment:
+		var deployment *appsv1.Deployment
+		if deployment, err = client.AppsV1().Deployments(namespace).Get(context.TODO(), name, metav1.GetOptions{}); err == nil {
+			labelSelector = deployment.Spec.Selector
+		}
+	case WorkloadStatefulSet:
+		var statefulSet *appsv1.StatefulSet
+		if statefulSet, err = client.AppsV1().StatefulSets(namespace).Get(context.TODO(), name, metav1.GetOptions{}); err == nil {
+			labelSelector = statefulSet.Spec.Selector
+		}
+	case WorkloadDaemonSet:
+		var daemonSet *appsv1.DaemonSet
+		if daemonSet, err = client.AppsV1().DaemonSets(namespace).Get(context.TODO(), name, metav1.GetOptions{}); err == nil {
+			labelSelector = daemonSet.Spec.Selector
+		}
+	default:
+		return "", errors.New("不支持的工作负载类型: " + kind)
+	}
+	if err != nil {
+		logger.Error(errors.New("获取" + kind + "详情失败, " + err.Error()))
+		return "", errors.New("获取" + kind + "详情失败, " + err.Error())
+	}
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return "", errors.New("解析" + kind + "的selector失败, " + err.Error())
+	}
+	return selector.String(), nil
+}
+
+// getPodTemplate 获取工作负载的pod模板
+func (w *workload) getPodTemplate(client *kubernetes.Clientset, kind, namespace, name string) (*corev1.PodTemplateSpec, error) {
+	var (
+		template *corev1.PodTemplateSpec
+		err      error
+	)
+	switch kind {
+	case WorkloadDeployment:
+		var deployment *appsv1.Deployment
+		if deployment, err = client.AppsV1().Deployments(namespace).Get(context.TODO(), name, metav1.GetOptions{}); err == nil {
+			template = &deployment.Spec.Template
+		}
+	case WorkloadStatefulSet:
+		var statefulSet *appsv1.StatefulSet
+		if statefulSet, err = client.AppsV1().StatefulSets(namespace).Get(context.TODO(), name, metav1.GetOptions{}); err == nil {
+			template = &statefulSet.Spec.Template
+		}
+	case WorkloadDaemonSet:
+		var daemonSet *appsv1.DaemonSet
+		if daemonSet, err = client.AppsV1().DaemonSets(namespace).Get(context.TODO(), name, metav1.GetOptions{}); err == nil {
+			template = &daemonSet.Spec.Template
+		}
+	default:
+		return nil, errors.New("不支持的工作负载类型: " + kind)
+	}
+	if err != nil {
+		logger.Error(errors.New("获取" + kind + "详情失败, " + err.Error()))
+		return nil, errors.New("获取" + kind + "详情失败, " + err.Error())
+	}
+	return template, nil
+}
+
+// patch 使用strategic merge patch修改工作负载，返回修改后的generation
+func (w *workload) patch(client *kubernetes.Clientset, kind, namespace, name string, patchData map[string]interface{}) (int64, error) {
+	patchBytes, err := json.Marshal(patchData)
+	if err != nil {
+		return 0, errors.New("序列化patchData失败, " + err.Error())
+	}
+	switch kind {
+	case WorkloadDeployment:
+		deployment, err := client.AppsV1().Deployments(namespace).Patch(context.TODO(), name, types.StrategicMergePatchType, patchBytes, metav1.PatchOptions{})
+		if err != nil {
+			return 0, err
+		}
+		return deployment.Generation, nil
+	case WorkloadStatefulSet:
+		statefulSet, err := client.AppsV1().StatefulSets(namespace).Patch(context.TODO(), name, types.StrategicMergePatchType, patchBytes, metav1.PatchOptions{})
+		if err != nil {
+			return 0, err
+		}
+		return statefulSet.Generation, nil
+	case WorkloadDaemonSet:
+		daemonSet, err := client.AppsV1().DaemonSets(namespace).Patch(context.TODO(), name, types.StrategicMergePatchType, patchBytes, metav1.PatchOptions{})
+		if err != nil {
+			return 0, err
+		}
+		return daemonSet.Generation, nil
+	}
+	return 0, errors.New("不支持的工作负载类型: " + kind)
+}
+
+// waitRevision 等待控制器处理指定generation的修改，返回工作负载最新的版本
+// 修改已经提交成功，等待超时或查询失败都不视为失败，返回当时获取到的版本
+func (w *workload) waitRevision(client *kubernetes.Clientset, kind, namespace, name string, generation int64) *WorkloadRevision {
+	revision := &WorkloadRevision{Kind: kind, Name: name, Namespace: namespace}
+	ctx, cancel := context.WithTimeout(context.Background(), revisionWaitTimeout)
+	defer cancel()
+	_ = wait.PollUntilContextCancel(ctx, 500*time.Millisecond, true, func(ctx context.Context) (bool, error) {
+		observed, err := w.getRevision(ctx, client, kind, namespace, name, revision)
+		if err != nil {
+			return false, nil
+		}
+		return observed >= generation, nil
+	})
+	return revision
+}
+
+// getRevision 将工作负载当前的版本写入revision，返回控制器已处理的generation
+func (w *workload) getRevision(ctx context.Context, client *kubernetes.Clientset, kind, namespace, name string, revision *WorkloadRevision) (int64, error) {
+	switch kind {
+	case WorkloadDeployment:
+		deployment, err := client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return 0, err
+		}
+		revision.Revision = getRevision(deployment)
+		replicaSets, err := Deployment.getReplicaSets(client, deployment)
+		if err != nil {
+			return 0, err
+		}
+		for i := range replicaSets {
+			if getRevision(&replicaSets[i]) == revision.Revision {
+				revision.RevisionName = replicaSets[i].Name
+			}
+		}
+		return deployment.Status.ObservedGeneration, nil
+	case WorkloadStatefulSet:
+		statefulSet, err := client.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return 0, err
+		}
+		if err := w.setControllerRevision(ctx, client, statefulSet, statefulSet.Spec.Selector, revision); err != nil {
+			return 0, err
+		}
+		return statefulSet.Status.ObservedGeneration, nil
+	case WorkloadDaemonSet:
+		daemonSet, err := client.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return 0, err
+		}
+		if err := w.setControllerRevision(ctx, client, daemonSet, daemonSet.Spec.Selector, revision); err != nil {
+			return 0, err
+		}
+		return daemonSet.Status.ObservedGeneration, nil
+	}
+	return 0, errors.New("不支持的工作负载类型: " + kind)
+}
+
+// setControllerRevision 将owner控制的ControllerRevision中revision号最大的一个写入revision
+func (w *workload) setControllerRevision(ctx context.Context, client *kubernetes.Clientset, owner metav1.Object, labelSelector *metav1.LabelSelector, revision *WorkloadRevision) error {
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return err
+	}
+	revisionList, err := client.AppsV1().ControllerRevisions(owner.GetNamespace()).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return err
+	}
+	for i := range revisionList.Items {
+		item := &revisionList.Items[i]
+		if metav1.IsControlledBy(item, owner) && item.Revision >= revision.Revision {
+			revision.Revision = item.Revision
+			revision.RevisionName = item.Name
+		}
+	}
+	return nil
+}
+
+// missingContainers 获取images中在pod模板里不存在的容器名称
+func (w *workload) missingContainers(template *corev1.PodTemplateSpec, images map[string]string) []string {
+	exists := make(map[string]bool)
+	for _, container := range template.Spec.Containers {
+		exists[container.Name] = true
+	}
+	for _, container := range template.Spec.InitContainers {
+		exists[container.Name] = true
+	}
+	missing := make([]string, 0)
+	for name := range images {
+		if !exists[name] {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}